@@ -4,4 +4,4 @@ go 1.25
 
 require golang.org/x/term v0.39.0
 
-require golang.org/x/sys v0.40.0 // indirect
+require golang.org/x/sys v0.40.0
@@ -12,15 +12,25 @@ import (
 )
 
 type GlobalOptions struct {
-	ConfigPath string
-	Timeout    time.Duration
-	JSON       bool
-	Quiet      bool
-	Verbose    bool
-	NoInput    bool
-	NoColor    bool
-	Help       bool
-	Version    bool
+	ConfigPath     string
+	Profile        string
+	PassphraseFile string
+	BaseURL        string
+	Timeout        time.Duration
+	Retries        int
+	MaxBackoff     time.Duration
+	RateLimit      int
+	RefreshSkew    time.Duration
+	TZ             string
+	NoCache        bool
+	CacheTTL       time.Duration
+	JSON           bool
+	Quiet          bool
+	Verbose        bool
+	NoInput        bool
+	NoColor        bool
+	Help           bool
+	Version        bool
 }
 
 func Run(args []string) int {
@@ -31,6 +41,10 @@ func Run(args []string) int {
 
 	pretty := !opts.JSON && termutil.IsTTY(os.Stdout)
 	printer := output.New(os.Stdout, os.Stderr, opts.Quiet, opts.Verbose, opts.JSON, pretty)
+	if pretty {
+		printer.Color = !opts.NoColor && os.Getenv("NO_COLOR") == ""
+		printer.Width = termutil.Width(os.Stdout)
+	}
 
 	if opts.Version {
 		printer.Write(versionString())
@@ -55,6 +69,14 @@ func Run(args []string) int {
 		return runResources(printer)
 	case "whoami":
 		return runWhoami(printer, opts)
+	case "profile":
+		return runProfile(printer, opts, rest[1:])
+	case "config":
+		return runConfig(printer, opts, rest[1:])
+	case "sync":
+		return runSync(printer, opts, rest[1:])
+	case "dev":
+		return runDev(printer, opts, rest[1:])
 	default:
 		printer.Errorf("unknown command: %s", rest[0])
 		printer.WriteErr("\n")
@@ -68,7 +90,17 @@ func parseGlobalFlags(args []string) (GlobalOptions, []string, int) {
 	fs := flag.NewFlagSet("oura", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	fs.StringVar(&opts.ConfigPath, "config", "", "config file path")
+	fs.StringVar(&opts.Profile, "profile", "", "config profile")
+	fs.StringVar(&opts.PassphraseFile, "passphrase-file", "", "file holding the config passphrase")
+	fs.StringVar(&opts.BaseURL, "base-url", "", "api base url")
 	fs.DurationVar(&opts.Timeout, "timeout", 30*time.Second, "http timeout")
+	fs.IntVar(&opts.Retries, "retries", 2, "retries for 429/5xx and network errors")
+	fs.DurationVar(&opts.MaxBackoff, "max-backoff", 30*time.Second, "maximum retry backoff")
+	fs.IntVar(&opts.RateLimit, "rate-limit", -1, "max requests per minute")
+	fs.DurationVar(&opts.RefreshSkew, "refresh-skew", -1, "refresh tokens this long before they expire")
+	fs.StringVar(&opts.TZ, "tz", "", "timezone for dates")
+	fs.BoolVar(&opts.NoCache, "no-cache", false, "bypass the response cache")
+	fs.DurationVar(&opts.CacheTTL, "cache-ttl", 0, "override cache ttl")
 	fs.BoolVar(&opts.JSON, "json", false, "compact json output")
 	fs.BoolVar(&opts.Quiet, "quiet", false, "suppress non-data output")
 	fs.BoolVar(&opts.Quiet, "q", false, "suppress non-data output")
@@ -85,6 +117,10 @@ func parseGlobalFlags(args []string) (GlobalOptions, []string, int) {
 		fmt.Fprint(os.Stderr, rootUsage())
 		return opts, nil, 2
 	}
+	if opts.Retries < 0 {
+		fmt.Fprintln(os.Stderr, "flag error: --retries must be >= 0")
+		return opts, nil, 2
+	}
 
 	return opts, fs.Args(), 0
 }
@@ -12,14 +12,17 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"os"
 	"os/exec"
 	"runtime"
 	"strings"
 	"time"
 
+	"github.com/mattjefferson/oura-cli/internal/cache"
 	"github.com/mattjefferson/oura-cli/internal/config"
-	"github.com/mattjefferson/oura-cli/internal/oura"
 	"github.com/mattjefferson/oura-cli/internal/output"
+	termutil "github.com/mattjefferson/oura-cli/internal/term"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
 )
 
 const defaultRedirectURI = "http://127.0.0.1:8797/callback"
@@ -39,7 +42,13 @@ func runAuth(printer *output.Printer, opts GlobalOptions, args []string) int {
 	case "status":
 		return runAuthStatus(printer, opts)
 	case "logout":
-		return runAuthLogout(printer, opts)
+		return runAuthLogout(printer, opts, args[1:])
+	case "refresh":
+		return runAuthRefresh(printer, opts)
+	case "token":
+		return runAuthToken(printer, opts)
+	case "scopes":
+		return runAuthScopes(printer, opts)
 	default:
 		printer.Errorf("unknown auth command: %s", args[0])
 		printer.WriteErr("\n")
@@ -58,6 +67,10 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 	var scopes string
 	var noOpen bool
 	var paste bool
+	var noPKCE bool
+	var publicClient bool
+	var remote bool
+	var bind string
 	var help bool
 
 	fs.StringVar(&clientID, "client-id", "", "oauth client id")
@@ -66,10 +79,14 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 	fs.StringVar(&scopes, "scopes", "", "scopes")
 	fs.BoolVar(&noOpen, "no-open", false, "do not open browser")
 	fs.BoolVar(&paste, "paste", false, "prompt for code")
+	fs.BoolVar(&noPKCE, "no-pkce", false, "disable PKCE")
+	fs.BoolVar(&publicClient, "public-client", false, "log in without a client secret")
+	fs.BoolVar(&remote, "remote", false, "headless login for a remote host")
+	fs.StringVar(&bind, "bind", "", "listen address for the callback server")
 	fs.BoolVar(&help, "help", false, "show help")
 	fs.BoolVar(&help, "h", false, "show help")
 
-	if err := fs.Parse(args); err != nil {
+	if err := parseFlags(fs, args); err != nil {
 		printer.Errorf("flag error: %v", err)
 		printer.WriteErr("\n")
 		printer.WriteErr(authLoginUsage())
@@ -80,7 +97,7 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 		return 0
 	}
 
-	loaded, err := loadConfig(opts)
+	loaded, err := loadConfig(opts, printer)
 	if err != nil {
 		printer.Errorf("config load failed: %v", err)
 		return 1
@@ -128,7 +145,15 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 		}
 		cfg.ClientID = val
 	}
-	if cfg.ClientSecret == "" {
+	if publicClient {
+		cfg.PublicClient = true
+	}
+	usePKCE := !noPKCE && !cfg.DisablePKCE
+	if cfg.PublicClient && !usePKCE {
+		printer.Errorf("public clients require PKCE; drop --no-pkce or disable_pkce")
+		return 2
+	}
+	if cfg.ClientSecret == "" && !cfg.PublicClient {
 		val, err := promptSecret("Client secret: ", printer, opts.NoInput)
 		if err != nil {
 			printer.Errorf("client secret required: %v", err)
@@ -146,7 +171,17 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 		printer.Errorf("state generation failed: %v", err)
 		return 1
 	}
-	authURL, err := oura.BuildAuthURL(cfg.ClientID, cfg.RedirectURI, state, cfg.Scopes)
+	var authOpts, exchangeOpts []oura.AuthOption
+	if usePKCE {
+		pkce, err := oura.NewPKCE()
+		if err != nil {
+			printer.Errorf("pkce generation failed: %v", err)
+			return 1
+		}
+		authOpts = append(authOpts, oura.WithCodeChallenge(pkce))
+		exchangeOpts = append(exchangeOpts, oura.WithCodeVerifier(pkce))
+	}
+	authURL, err := oura.BuildAuthURL(loaded.endpoint(opts), cfg.ClientID, cfg.RedirectURI, state, cfg.Scopes, authOpts...)
 	if err != nil {
 		printer.Errorf("auth url build failed: %v", err)
 		return 1
@@ -155,35 +190,64 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 	var code string
 	if paste {
 		printer.Infof("Open this URL:\n%s", authURL)
-		val, err := promptString("Authorization code: ", printer, opts.NoInput)
+		val, err := promptString("Redirect URL or code: ", printer, opts.NoInput)
 		if err != nil {
 			printer.Errorf("authorization code required: %v", err)
 			return 2
 		}
-		code = val
+		code, err = parseAuthCode(val, state)
+		if err != nil {
+			printer.Errorf("auth failed: %v", err)
+			return 2
+		}
 	} else {
 		if err := validateLoopbackRedirect(cfg.RedirectURI); err != nil {
 			printer.Errorf("redirect uri invalid for local server: %v", err)
 			printer.Errorf("use --paste for manual flow")
 			return 2
 		}
+		if bind == "" {
+			u, _ := url.Parse(cfg.RedirectURI)
+			bind = u.Host
+		}
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 		defer cancel()
 
-		codeCh := make(chan string, 1)
-		errCh := make(chan error, 1)
+		canPaste := remote && !opts.NoInput && termutil.IsTTY(os.Stdin)
+		codeCh := make(chan string, 2)
+		errCh := make(chan error, 2)
 		go func() {
-			code, err := waitForAuthCode(ctx, cfg.RedirectURI, state)
+			code, err := waitForAuthCode(ctx, cfg.RedirectURI, bind, state)
 			if err != nil {
+				if canPaste && ctx.Err() == nil {
+					printer.Infof("callback server stopped: %v; paste the redirect URL instead", err)
+					return
+				}
 				errCh <- err
 				return
 			}
 			codeCh <- code
 		}()
 
-		if !noOpen {
+		switch {
+		case remote:
+			printRemoteHint(printer, cfg.RedirectURI, bind, authURL, canPaste)
+			if canPaste {
+				go func() {
+					val, err := promptString("Redirect URL or code: ", printer, opts.NoInput)
+					if err == nil {
+						val, err = parseAuthCode(val, state)
+					}
+					if err != nil {
+						errCh <- err
+						return
+					}
+					codeCh <- val
+				}()
+			}
+		case !noOpen:
 			openBrowser(authURL, printer)
-		} else {
+		default:
 			printer.Infof("Open this URL:\n%s", authURL)
 		}
 
@@ -199,24 +263,17 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 	}
 
 	httpClient := &http.Client{Timeout: opts.Timeout}
-	tokenResp, err := oura.ExchangeCode(context.Background(), httpClient, cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI, code)
+	tokenResp, err := oura.ExchangeCode(context.Background(), httpClient, loaded.endpoint(opts), cfg.ClientID, cfg.ClientSecret, cfg.RedirectURI, code, exchangeOpts...)
 	if err != nil {
 		printer.Errorf("token exchange failed: %v", err)
 		return 1
 	}
 
-	token := &config.Token{
-		AccessToken:  tokenResp.AccessToken,
-		RefreshToken: tokenResp.RefreshToken,
-		TokenType:    tokenResp.TokenType,
-	}
-	if tokenResp.ExpiresIn > 0 {
-		expiresAt := time.Now().UTC().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-		token.ExpiresAt = expiresAt.Format(time.RFC3339)
-	}
+	token := &config.Token{}
+	applyTokenResponse(token, tokenResp)
 	cfg.Token = token
 
-	if err := config.Save(loaded.Path, cfg); err != nil {
+	if err := loaded.saveConfig(cfg); err != nil {
 		printer.Errorf("config save failed: %v", err)
 		return 1
 	}
@@ -229,12 +286,13 @@ func runAuthLogin(printer *output.Printer, opts GlobalOptions, args []string) in
 }
 
 func runAuthStatus(printer *output.Printer, opts GlobalOptions) int {
-	loaded, err := loadConfig(opts)
+	loaded, err := loadConfig(opts, printer)
 	if err != nil {
 		printer.Errorf("config load failed: %v", err)
 		return 1
 	}
 	summary := statusSummary(loaded.Cfg)
+	summary["profile"] = loaded.Profile
 	if opts.JSON {
 		b, err := json.Marshal(summary)
 		if err != nil {
@@ -248,9 +306,9 @@ func runAuthStatus(printer *output.Printer, opts GlobalOptions) int {
 		return 0
 	}
 	if summary["token_present"].(bool) {
-		printer.Infof("logged in")
+		printer.Infof("logged in (profile %s)", loaded.Profile)
 	} else {
-		printer.Infof("not logged in")
+		printer.Infof("not logged in (profile %s)", loaded.Profile)
 		return 3
 	}
 	if v, ok := summary["expires_at"]; ok {
@@ -268,25 +326,239 @@ func runAuthStatus(printer *output.Printer, opts GlobalOptions) int {
 	return 0
 }
 
-func runAuthLogout(printer *output.Printer, opts GlobalOptions) int {
-	loaded, err := loadConfig(opts)
+func runAuthLogout(printer *output.Printer, opts GlobalOptions, args []string) int {
+	fs := flag.NewFlagSet("auth logout", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var localOnly bool
+	var help bool
+
+	fs.BoolVar(&localOnly, "local-only", false, "do not revoke tokens with Oura")
+	fs.BoolVar(&help, "help", false, "show help")
+	fs.BoolVar(&help, "h", false, "show help")
+
+	if err := parseFlags(fs, args); err != nil {
+		printer.Errorf("flag error: %v", err)
+		printer.WriteErr("\n")
+		printer.WriteErr(authLogoutUsage())
+		return 2
+	}
+	if help {
+		printer.Write(authLogoutUsage())
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		printer.Errorf("unexpected argument: %s", fs.Args()[0])
+		return 2
+	}
+
+	loaded, err := loadConfig(opts, printer)
 	if err != nil {
 		printer.Errorf("config load failed: %v", err)
 		return 1
 	}
-	if loaded.Cfg.Token == nil || loaded.Cfg.Token.AccessToken == "" {
+	if loaded.Env.AccessToken || loaded.Env.RefreshToken {
+		printer.Infof("token from OURA_ACCESS_TOKEN/OURA_REFRESH_TOKEN left untouched; unset it to stop using it")
+	}
+	stored, err := loaded.reload()
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	if stored.Token == nil || stored.Token.AccessToken == "" {
 		printer.Infof("no stored token")
 		return 0
 	}
-	loaded.Cfg.Token = nil
-	if err := config.Save(loaded.Path, loaded.Cfg); err != nil {
+
+	if !localOnly {
+		httpClient := &http.Client{Timeout: opts.Timeout}
+		endpoint := loaded.endpoint(opts)
+		failed := false
+		for _, t := range []struct{ name, value string }{
+			{"access token", stored.Token.AccessToken},
+			{"refresh token", stored.Token.RefreshToken},
+		} {
+			if t.value == "" {
+				continue
+			}
+			err := oura.RevokeToken(context.Background(), httpClient, endpoint, t.value)
+			switch {
+			case err == nil:
+				printer.Infof("%s revoked", t.name)
+			case oura.IsInvalidToken(err):
+				printer.Infof("%s already invalid", t.name)
+			default:
+				printer.Errorf("%s revoke failed: %v", t.name, err)
+				failed = true
+			}
+		}
+		if failed {
+			printer.Errorf("tokens kept; retry, or use --local-only to remove them without revoking")
+			return 1
+		}
+	}
+
+	stored.Token = nil
+	if err := loaded.saveConfig(stored); err != nil {
 		printer.Errorf("config save failed: %v", err)
 		return 1
 	}
+	if err := cache.New(loaded.dataPath("cache"), 0, nil).Clear(); err != nil {
+		printer.Errorf("cache clear failed: %v", err)
+		return 1
+	}
 	printer.Infof("logged out")
 	return 0
 }
 
+func runAuthRefresh(printer *output.Printer, opts GlobalOptions) int {
+	loaded, err := loadConfig(opts, printer)
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	if loaded.Cfg.Token == nil || loaded.Cfg.Token.RefreshToken == "" {
+		printer.Errorf("no refresh token; run oura auth login")
+		return 3
+	}
+	tokens := newTokenSource(&loaded, opts, &http.Client{Timeout: opts.Timeout}, printer)
+	if _, err := tokens.refresh(context.Background(), true); err != nil {
+		if errors.Is(err, errTokenNotSaved) {
+			printer.Errorf("%v; the previous refresh token may no longer work, run oura auth login if later requests fail", err)
+			return 1
+		}
+		printer.Errorf("token refresh failed: %v", err)
+		return 3
+	}
+	if !tokens.persistAllowed() {
+		printer.Infof("token came from the environment; refreshed token not saved")
+	}
+	token := loaded.Cfg.Token
+	if opts.JSON {
+		b, err := json.Marshal(map[string]string{
+			"expires_at": token.ExpiresAt,
+			"token_type": token.TokenType,
+		})
+		if err != nil {
+			printer.Errorf("json encode failed: %v", err)
+			return 1
+		}
+		if err := printer.PrintJSON(b); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
+	if token.ExpiresAt != "" {
+		printer.Write("token refreshed; expires at " + token.ExpiresAt + "\n")
+	} else {
+		printer.Write("token refreshed\n")
+	}
+	return 0
+}
+
+func runAuthToken(printer *output.Printer, opts GlobalOptions) int {
+	loaded, err := loadConfig(opts, printer)
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	tokens := newTokenSource(&loaded, opts, &http.Client{Timeout: opts.Timeout}, printer)
+	token, err := tokens.Token(context.Background())
+	if err != nil {
+		printer.Errorf("not logged in: %v", err)
+		return 3
+	}
+	if expiresWithin(loaded.Cfg.Token, 0) {
+		printer.Errorf("access token expired at %s and could not be refreshed", loaded.Cfg.Token.ExpiresAt)
+		return 3
+	}
+	if opts.JSON {
+		b, err := json.Marshal(map[string]string{
+			"access_token": token,
+			"token_type":   loaded.Cfg.Token.TokenType,
+			"expires_at":   loaded.Cfg.Token.ExpiresAt,
+		})
+		if err != nil {
+			printer.Errorf("json encode failed: %v", err)
+			return 1
+		}
+		if err := printer.PrintJSON(b); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
+	printer.Write(token + "\n")
+	return 0
+}
+
+func runAuthScopes(printer *output.Printer, opts GlobalOptions) int {
+	loaded, err := loadConfig(opts, printer)
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	if loaded.Cfg.Token == nil || loaded.Cfg.Token.AccessToken == "" {
+		printer.Errorf("not logged in")
+		return 3
+	}
+	granted := loaded.Cfg.Token.GrantedScopes()
+
+	type resourceScopes struct {
+		Resource string   `json:"resource"`
+		Scopes   []string `json:"scopes"`
+		Missing  []string `json:"missing,omitempty"`
+	}
+	var rows []resourceScopes
+	for _, r := range oura.Resources() {
+		row := resourceScopes{Resource: r.Key, Scopes: r.Scopes}
+		if len(granted) > 0 {
+			row.Missing = r.MissingScopes(granted)
+		}
+		rows = append(rows, row)
+	}
+
+	if opts.JSON {
+		b, err := json.Marshal(map[string]any{
+			"granted":   granted,
+			"requested": loaded.Cfg.Scopes,
+			"resources": rows,
+		})
+		if err != nil {
+			printer.Errorf("json encode failed: %v", err)
+			return 1
+		}
+		if err := printer.PrintJSON(b); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
+
+	if len(granted) == 0 {
+		printer.Infof("granted scopes unknown; run oura auth refresh or oura auth login to record them")
+	} else {
+		printer.Infof("granted: %s", strings.Join(granted, " "))
+	}
+	table := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		status := "ok"
+		switch {
+		case len(granted) == 0:
+			status = "unknown"
+		case len(row.Missing) > 0:
+			status = "missing " + strings.Join(row.Missing, ",")
+		}
+		table = append(table, []string{row.Resource, strings.Join(row.Scopes, ","), status})
+	}
+	if err := printer.PrintTable([]string{"resource", "scopes", "status"}, table); err != nil {
+		printer.Errorf("output failed: %v", err)
+		return 1
+	}
+	return 0
+}
+
 func randomState(n int) (string, error) {
 	b := make([]byte, n)
 	if _, err := rand.Read(b); err != nil {
@@ -295,7 +567,58 @@ func randomState(n int) (string, error) {
 	return base64.RawURLEncoding.EncodeToString(b), nil
 }
 
-func waitForAuthCode(ctx context.Context, redirectURI, state string) (string, error) {
+func printRemoteHint(printer *output.Printer, redirectURI, bind, authURL string, canPaste bool) {
+	redirect, _ := url.Parse(redirectURI)
+	host, port, err := net.SplitHostPort(bind)
+	if err != nil {
+		host, port = "127.0.0.1", redirect.Port()
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "127.0.0.1"
+	}
+	machine, err := os.Hostname()
+	if err != nil || machine == "" {
+		machine = "<this-host>"
+	}
+	printer.Infof("Callback server listening on %s.", bind)
+	printer.Infof("From the machine with the browser, forward the callback port:\n  ssh -N -L %s:%s %s",
+		redirect.Port(), net.JoinHostPort(host, port), machine)
+	printer.Infof("Then open this URL there:\n%s", authURL)
+	if canPaste {
+		printer.Infof("Or, if the redirect page fails to load, paste its URL (or just the code) below.")
+	}
+}
+
+func parseAuthCode(input, state string) (string, error) {
+	input = strings.TrimSpace(input)
+	if input == "" {
+		return "", errors.New("missing code")
+	}
+	raw := input
+	if _, query, ok := strings.Cut(input, "?"); ok {
+		raw = query
+	} else if !strings.Contains(input, "code=") && !strings.Contains(input, "error=") {
+		return input, nil
+	}
+	raw, _, _ = strings.Cut(raw, "#")
+	q, err := url.ParseQuery(raw)
+	if err != nil {
+		return "", fmt.Errorf("invalid redirect url: %w", err)
+	}
+	if errParam := q.Get("error"); errParam != "" {
+		return "", fmt.Errorf("authorization error: %s", errParam)
+	}
+	if state != "" && q.Get("state") != state {
+		return "", errors.New("state mismatch")
+	}
+	code := q.Get("code")
+	if code == "" {
+		return "", errors.New("missing code")
+	}
+	return code, nil
+}
+
+func waitForAuthCode(ctx context.Context, redirectURI, bind, state string) (string, error) {
 	u, err := url.Parse(redirectURI)
 	if err != nil {
 		return "", err
@@ -344,11 +667,11 @@ func waitForAuthCode(ctx context.Context, redirectURI, state string) (string, er
 		}
 	})
 
-	ln, err := net.Listen("tcp", u.Host)
+	ln, err := net.Listen("tcp", bind)
 	if err != nil {
 		return "", err
 	}
-	srv := &http.Server{Addr: u.Host, Handler: mux}
+	srv := &http.Server{Addr: bind, Handler: mux}
 	go func() {
 		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
 			select {
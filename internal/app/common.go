@@ -2,51 +2,339 @@ package app
 
 import (
 	"bufio"
-	"encoding/json"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"flag"
+	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/mattjefferson/oura-cli/internal/cache"
 	"github.com/mattjefferson/oura-cli/internal/config"
-	"github.com/mattjefferson/oura-cli/internal/oura"
+	"github.com/mattjefferson/oura-cli/internal/filelock"
+	"github.com/mattjefferson/oura-cli/internal/offline"
 	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/internal/ratelimit"
+	"github.com/mattjefferson/oura-cli/internal/secrets"
 	termutil "github.com/mattjefferson/oura-cli/internal/term"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
 )
 
 type loadedConfig struct {
-	Path string
-	Cfg  config.Config
-	Env  config.EnvOverrides
+	Path    string
+	Profile string
+	Cfg     config.Config
+	Env     config.EnvOverrides
+	Secrets secrets.Store
+	unlock  config.Unlocker
 }
 
-func loadConfig(opts GlobalOptions) (loadedConfig, error) {
-	path := opts.ConfigPath
-	if path == "" {
-		p, err := config.DefaultPath()
-		if err != nil {
-			return loadedConfig{}, err
+func (l loadedConfig) save() error {
+	return l.saveConfig(l.Cfg)
+}
+
+func (l loadedConfig) saveConfig(cfg config.Config) error {
+	lock, err := lockConfig(l.Path)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return l.writeConfig(cfg)
+}
+
+func (l loadedConfig) writeConfig(cfg config.Config) error {
+	previous, _, err := config.Load(l.Path, l.Profile, l.unlock)
+	if err != nil {
+		return err
+	}
+	l.Env.Restore(&cfg, previous)
+	if l.Secrets != nil {
+		if cfg, err = externalizeSecrets(l.Secrets, secretKeyPrefix(l.Path, l.Profile), cfg, previous); err != nil {
+			return err
 		}
-		path = p
 	}
-	cfg, _, err := config.Load(path)
+	return config.Save(l.Path, l.Profile, cfg, l.unlock)
+}
+
+func (l loadedConfig) baseURL(opts GlobalOptions) string {
+	if opts.BaseURL != "" {
+		return opts.BaseURL
+	}
+	if l.Cfg.APIBaseURL != "" {
+		return l.Cfg.APIBaseURL
+	}
+	return oura.DefaultBaseURL
+}
+
+func (l loadedConfig) dataPath(name string) string {
+	return profileDataPath(l.Path, l.Profile, name)
+}
+
+func profileDataPath(configPath, profile, name string) string {
+	if profile == config.DefaultProfile {
+		return filepath.Join(configDataDir(configPath), name)
+	}
+	return filepath.Join(configDataDir(configPath), "profiles", profile, name)
+}
+
+func configDataDir(configPath string) string {
+	dir, base := filepath.Split(configPath)
+	if base == config.DefaultFileName {
+		return filepath.Clean(dir)
+	}
+	return filepath.Join(dir, strings.TrimSuffix(base, filepath.Ext(base))+".d")
+}
+
+func (l loadedConfig) cacheScope() string {
+	if l.Env.AccessToken && l.Cfg.Token != nil {
+		sum := sha256.Sum256([]byte(l.Cfg.Token.AccessToken))
+		return "token:" + hex.EncodeToString(sum[:8])
+	}
+	path, err := filepath.Abs(l.Path)
+	if err != nil {
+		path = l.Path
+	}
+	return "config:" + path + "#" + l.Profile
+}
+
+func (l loadedConfig) refreshSkew(opts GlobalOptions) time.Duration {
+	if opts.RefreshSkew >= 0 {
+		return opts.RefreshSkew
+	}
+	switch {
+	case l.Cfg.RefreshSkew < 0:
+		return 0
+	case l.Cfg.RefreshSkew > 0:
+		return time.Duration(l.Cfg.RefreshSkew) * time.Second
+	}
+	return defaultRefreshSkew
+}
+
+func (l loadedConfig) reload() (config.Config, error) {
+	cfg, _, err := config.Load(l.Path, l.Profile, l.unlock)
+	if err != nil {
+		return cfg, err
+	}
+	if l.Secrets != nil {
+		err = resolveSecrets(l.Secrets, &cfg)
+	}
+	return cfg, err
+}
+
+func (l loadedConfig) rateLimit(opts GlobalOptions) int {
+	if opts.RateLimit >= 0 {
+		return opts.RateLimit
+	}
+	return l.Cfg.RateLimit
+}
+
+func (l loadedConfig) endpoint(opts GlobalOptions) oura.Endpoint {
+	endpoint := oura.EndpointForBaseURL(l.baseURL(opts))
+	if l.Cfg.AuthorizeURL != "" {
+		endpoint.AuthorizeURL = l.Cfg.AuthorizeURL
+	}
+	if l.Cfg.TokenURL != "" {
+		endpoint.TokenURL = l.Cfg.TokenURL
+	}
+	if l.Cfg.RevokeURL != "" {
+		endpoint.RevokeURL = l.Cfg.RevokeURL
+	}
+	return endpoint
+}
+
+func configPath(opts GlobalOptions) (string, error) {
+	if opts.ConfigPath != "" {
+		return opts.ConfigPath, nil
+	}
+	return config.DefaultPath()
+}
+
+func loadConfigFile(opts GlobalOptions, printer *output.Printer, unlock config.Unlocker) (string, config.File, error) {
+	path, err := configPath(opts)
+	if err != nil {
+		return "", config.File{}, err
+	}
+	file, _, err := config.LoadFile(path, unlock)
+	if err != nil || !file.Legacy() {
+		return path, file, err
+	}
+	lock, err := lockConfig(path)
+	if err != nil {
+		return "", file, err
+	}
+	defer lock.Release()
+	file, err = migrateConfigFile(path, printer, unlock)
+	return path, file, err
+}
+
+func lockConfigFile(opts GlobalOptions, printer *output.Printer, unlock config.Unlocker) (string, config.File, *filelock.Lock, error) {
+	path, err := configPath(opts)
+	if err != nil {
+		return "", config.File{}, nil, err
+	}
+	lock, err := lockConfig(path)
+	if err != nil {
+		return path, config.File{}, nil, err
+	}
+	file, err := migrateConfigFile(path, printer, unlock)
+	if err != nil {
+		lock.Release()
+		return path, file, nil, err
+	}
+	return path, file, lock, nil
+}
+
+func migrateConfigFile(path string, printer *output.Printer, unlock config.Unlocker) (config.File, error) {
+	file, _, err := config.LoadFile(path, unlock)
+	if err != nil || !file.Legacy() {
+		return file, err
+	}
+	if err := config.SaveFile(path, file); err != nil {
+		return file, fmt.Errorf("config migration failed: %w", err)
+	}
+	printer.Debugf("migrated %s to profile %q", path, config.DefaultProfile)
+	return file, nil
+}
+
+func lockConfig(path string) (*filelock.Lock, error) {
+	lock, err := filelock.Acquire(path + ".lock")
+	if err != nil {
+		return nil, fmt.Errorf("config lock failed: %w", err)
+	}
+	return lock, nil
+}
+
+func loadConfig(opts GlobalOptions, printer *output.Printer) (loadedConfig, error) {
+	unlock := configUnlocker(opts, printer)
+	path, file, err := loadConfigFile(opts, printer, unlock)
 	if err != nil {
 		return loadedConfig{}, err
 	}
-	env := config.ApplyEnv(&cfg)
-	return loadedConfig{Path: path, Cfg: cfg, Env: env}, nil
+	profile := file.Resolve(selectValue(opts.Profile, os.Getenv("OURA_PROFILE")))
+	if !config.ValidProfileName(profile) {
+		return loadedConfig{}, fmt.Errorf("invalid profile name: %q", profile)
+	}
+	cfg := file.Profiles[profile]
+	loaded := loadedConfig{Path: path, Profile: profile, unlock: unlock}
+	if loaded.Secrets, err = openSecretStore(path, cfg, opts, printer); err != nil {
+		return loadedConfig{}, fmt.Errorf("secret store: %w", err)
+	}
+	switch {
+	case loaded.Secrets != nil:
+		if hasPlaintextSecrets(cfg) {
+			if err := loaded.saveConfig(cfg); err != nil {
+				return loadedConfig{}, fmt.Errorf("secret store: %w", err)
+			}
+			printer.Debugf("moved secrets for profile %q into the %s store", profile, cfg.SecretStore.Backend)
+		}
+		if err := resolveSecrets(loaded.Secrets, &cfg); err != nil {
+			return loadedConfig{}, fmt.Errorf("secret store: %w", err)
+		}
+	case hasSecretRefs(cfg):
+		return loadedConfig{}, fmt.Errorf("profile %q references secrets but secret_store is not configured", profile)
+	}
+	loaded.Env = config.ApplyEnv(&cfg)
+	loaded.Cfg = cfg
+	return loaded, nil
+}
+
+func loadClient(opts GlobalOptions, printer *output.Printer, resource oura.Resource, sandbox bool) (*oura.Client, int, error) {
+	loaded, err := loadConfig(opts, printer)
+	if err != nil {
+		return nil, 1, err
+	}
+	return resourceClient(&loaded, opts, printer, resource, sandbox)
+}
+
+func resourceClient(loaded *loadedConfig, opts GlobalOptions, printer *output.Printer, resource oura.Resource, sandbox bool) (*oura.Client, int, error) {
+	if !sandbox {
+		if err := checkScopes(*loaded, resource); err != nil {
+			return nil, 3, err
+		}
+	}
+	return newClient(loaded, opts, printer)
+}
+
+func checkScopes(loaded loadedConfig, resource oura.Resource) error {
+	if loaded.Env.AccessToken {
+		return nil
+	}
+	granted := loaded.Cfg.Token.GrantedScopes()
+	if len(granted) == 0 {
+		return nil
+	}
+	missing := resource.MissingScopes(granted)
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s needs scope %s, which this login was not granted (granted: %s); run: oura auth login --scopes %s",
+		resource.Key, strings.Join(missing, ","), strings.Join(granted, ","), strings.Join(append(granted, missing...), ","))
 }
 
-func loadClient(opts GlobalOptions, printer *output.Printer) (*oura.Client, int, error) {
-	loaded, err := loadConfig(opts)
+func loadSourceClient(dir string, printer *output.Printer) (*oura.Client, int, error) {
+	info, err := os.Stat(dir)
 	if err != nil {
 		return nil, 1, err
 	}
+	if !info.IsDir() {
+		return nil, 2, errors.New("source is not a directory")
+	}
+	client := oura.NewClient(
+		oura.WithBaseURL(offline.BaseURL),
+		oura.WithHTTPClient(&http.Client{Transport: offline.Transport{Dir: dir}}),
+		oura.WithTokenSource(oura.StaticToken("offline")),
+		oura.WithLogger(printer),
+	)
+	return client, 0, nil
+}
+
+func newClient(loaded *loadedConfig, opts GlobalOptions, printer *output.Printer) (*oura.Client, int, error) {
 	if loaded.Cfg.Token == nil || loaded.Cfg.Token.AccessToken == "" {
 		return nil, 3, errors.New("not authenticated")
 	}
-	client := oura.NewClient(&loaded.Cfg, loaded.Path, loaded.Env, opts.Timeout, printer)
-	return client, 0, nil
+	httpClient := &http.Client{Timeout: opts.Timeout}
+	tokens := newTokenSource(loaded, opts, httpClient, printer)
+	clientOpts := []oura.Option{
+		oura.WithBaseURL(loaded.baseURL(opts)),
+		oura.WithHTTPClient(httpClient),
+		oura.WithTokenSource(tokens),
+		oura.WithLogger(printer),
+		oura.WithRetry(oura.RetryPolicy{MaxRetries: opts.Retries, MaxBackoff: opts.MaxBackoff}),
+	}
+	if limit := loaded.rateLimit(opts); limit > 0 {
+		clientOpts = append(clientOpts, oura.WithLimiter(ratelimit.NewFileBucket(loaded.dataPath("ratelimit.json"), limit)))
+	}
+	if !opts.NoCache {
+		dir := cache.New(loaded.dataPath("cache"), opts.CacheTTL, printer.Debugf)
+		clientOpts = append(clientOpts, oura.WithCache(dir, dir.TTL), oura.WithCacheScope(loaded.cacheScope()))
+	}
+	return oura.NewClient(clientOpts...), 0, nil
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			break
+		}
+		consumed := len(args) - len(rest)
+		if consumed > 0 && args[consumed-1] == "--" {
+			positional = append(positional, rest...)
+			break
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+	return fs.Parse(append([]string{"--"}, positional...))
 }
 
 func promptString(prompt string, out *output.Printer, noInput bool) (string, error) {
@@ -75,6 +363,51 @@ func promptSecret(prompt string, out *output.Printer, noInput bool) (string, err
 	return termutil.ReadPassword(prompt, os.Stderr)
 }
 
+func configUnlocker(opts GlobalOptions, printer *output.Printer) config.Unlocker {
+	var once sync.Once
+	var pass string
+	var err error
+	return func() (string, error) {
+		once.Do(func() {
+			pass, err = configPassphrase(opts, printer, false)
+		})
+		return pass, err
+	}
+}
+
+func configPassphrase(opts GlobalOptions, printer *output.Printer, confirm bool) (string, error) {
+	if v := os.Getenv("OURA_CONFIG_PASSPHRASE"); v != "" {
+		return v, nil
+	}
+	if file := selectValue(opts.PassphraseFile, os.Getenv("OURA_CONFIG_PASSPHRASE_FILE")); file != "" {
+		pass, err := readSecretFile(file)
+		if err != nil {
+			return "", fmt.Errorf("passphrase file: %w", err)
+		}
+		if pass == "" {
+			return "", errors.New("passphrase file is empty")
+		}
+		return pass, nil
+	}
+	pass, err := promptSecret("Config passphrase: ", printer, opts.NoInput)
+	if err != nil {
+		return "", fmt.Errorf("%w (%v)", config.ErrEncrypted, err)
+	}
+	if pass == "" {
+		return "", errors.New("empty passphrase")
+	}
+	if confirm {
+		again, err := promptSecret("Repeat passphrase: ", printer, opts.NoInput)
+		if err != nil {
+			return "", err
+		}
+		if again != pass {
+			return "", errors.New("passphrases do not match")
+		}
+	}
+	return pass, nil
+}
+
 func readSecretFile(path string) (string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
@@ -159,20 +492,3 @@ func statusSummary(cfg config.Config) map[string]any {
 	}
 	return result
 }
-
-func apiErrorMessage(body []byte) string {
-	text := strings.TrimSpace(string(body))
-	if text == "" {
-		return ""
-	}
-	var payload map[string]any
-	if err := json.Unmarshal(body, &payload); err == nil {
-		if msg, ok := payload["message"].(string); ok && msg != "" {
-			return msg
-		}
-		if msg, ok := payload["error"].(string); ok && msg != "" {
-			return msg
-		}
-	}
-	return text
-}
@@ -0,0 +1,125 @@
+package app
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/output"
+)
+
+func runConfig(printer *output.Printer, opts GlobalOptions, args []string) int {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		printer.Write(configUsage())
+		return 0
+	}
+	if args[0] == "set" {
+		return runConfigSet(printer, opts, args[1:])
+	}
+	if len(args) > 1 {
+		printer.Errorf("unexpected argument: %s", args[1])
+		return 2
+	}
+	switch args[0] {
+	case "encrypt":
+		return runConfigEncrypt(printer, opts)
+	case "decrypt":
+		return runConfigDecrypt(printer, opts)
+	default:
+		printer.Errorf("unknown config command: %s", args[0])
+		printer.WriteErr("\n")
+		printer.WriteErr(configUsage())
+		return 2
+	}
+}
+
+func runConfigEncrypt(printer *output.Printer, opts GlobalOptions) int {
+	path, file, lock, err := lockConfigFile(opts, printer, nil)
+	if err != nil {
+		if errors.Is(err, config.ErrEncrypted) {
+			printer.Infof("%s is already encrypted", path)
+			return 0
+		}
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	pass, err := configPassphrase(opts, printer, true)
+	if err != nil {
+		printer.Errorf("%v", err)
+		return 1
+	}
+	file.Encrypt(pass)
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	printer.Infof("encrypted %s", path)
+	return 0
+}
+
+func runConfigDecrypt(printer *output.Printer, opts GlobalOptions) int {
+	path, file, lock, err := lockConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	if !file.Encrypted() {
+		printer.Infof("%s is not encrypted", path)
+		return 0
+	}
+	file.Decrypt()
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	printer.Infof("decrypted %s", path)
+	return 0
+}
+
+func runConfigSet(printer *output.Printer, opts GlobalOptions, args []string) int {
+	if len(args) != 2 {
+		printer.Errorf("key and value required")
+		printer.WriteErr("\n")
+		printer.WriteErr(configUsage())
+		return 2
+	}
+	key, value := args[0], strings.TrimSpace(args[1])
+	switch key {
+	case "timezone":
+		if _, err := time.LoadLocation(value); err != nil || value == "" {
+			printer.Errorf("invalid timezone %q (use an IANA name like America/Chicago, or Local)", value)
+			return 2
+		}
+	default:
+		printer.Errorf("unknown config key: %s", key)
+		return 2
+	}
+
+	path, file, lock, err := lockConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	profile := file.Resolve(selectValue(opts.Profile, os.Getenv("OURA_PROFILE")))
+	if !config.ValidProfileName(profile) {
+		printer.Errorf("invalid profile name: %q", profile)
+		return 2
+	}
+	cfg := file.Profiles[profile]
+	cfg.Timezone = value
+	if value == "Local" {
+		cfg.Timezone = ""
+	}
+	file.Profiles[profile] = cfg
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	printer.Infof("set %s for profile %s", key, profile)
+	return 0
+}
@@ -0,0 +1,214 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+func userLocation(opts GlobalOptions, configured string) (*time.Location, error) {
+	name := selectValue(opts.TZ, selectValue(os.Getenv("OURA_TZ"), configured))
+	if name == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone %q: %w", name, err)
+	}
+	return loc, nil
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+func parseDateExpr(value string, now time.Time) (time.Time, time.Time, error) {
+	today := startOfDay(now)
+	loc := now.Location()
+	switch v := strings.ToLower(strings.TrimSpace(value)); v {
+	case "today":
+		return today, today, nil
+	case "yesterday":
+		d := today.AddDate(0, 0, -1)
+		return d, d, nil
+	case "this-week":
+		monday := today.AddDate(0, 0, -daysSinceMonday(today))
+		return monday, today, nil
+	case "last-week":
+		monday := today.AddDate(0, 0, -daysSinceMonday(today)-7)
+		return monday, monday.AddDate(0, 0, 6), nil
+	case "this-month":
+		first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, loc)
+		return first, today, nil
+	case "last-month":
+		first := time.Date(today.Year(), today.Month()-1, 1, 0, 0, 0, 0, loc)
+		return first, first.AddDate(0, 1, -1), nil
+	default:
+		if n, unit, ok := parseRelative(v); ok && n <= 0 {
+			d := today.AddDate(0, 0, n*unit)
+			return d, d, nil
+		}
+		if strings.Contains(v, "-w") {
+			monday, err := parseISOWeek(v, loc)
+			if err != nil {
+				return time.Time{}, time.Time{}, err
+			}
+			return monday, monday.AddDate(0, 0, 6), nil
+		}
+		d, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(value), loc)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid date %q (use YYYY-MM-DD, today, yesterday, -7d, this-week, last-week, this-month, last-month or YYYY-Www)", value)
+		}
+		return d, d, nil
+	}
+}
+
+func endOfDay(day time.Time) time.Time {
+	return day.AddDate(0, 0, 1)
+}
+
+var wallClockLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+
+func parseDateTimeExpr(value string, now time.Time) (time.Time, time.Time, error) {
+	v := strings.TrimSpace(value)
+	if t, err := time.Parse(time.RFC3339, v); err == nil {
+		return t, t, nil
+	}
+	for _, layout := range wallClockLayouts {
+		t, err := time.ParseInLocation(layout, v, now.Location())
+		if err != nil {
+			continue
+		}
+		if t.Format(layout) != v {
+			return time.Time{}, time.Time{}, fmt.Errorf("datetime %q does not exist in %s (daylight saving gap)", value, now.Location())
+		}
+		return t, t, nil
+	}
+	if strings.EqualFold(v, "now") {
+		return now, now, nil
+	}
+	if strings.HasPrefix(v, "-") {
+		if d, err := time.ParseDuration(v); err == nil {
+			t := now.Add(d)
+			return t, t, nil
+		}
+	}
+	start, end, err := parseDateExpr(v, now)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid datetime %q (use RFC3339, YYYY-MM-DDTHH:MM, now, -6h or a date expression)", value)
+	}
+	return start, endOfDay(end), nil
+}
+
+func parseRelative(v string) (int, int, bool) {
+	if len(v) < 2 || (v[0] != '-' && v[0] != '+') {
+		return 0, 0, false
+	}
+	var unit int
+	switch v[len(v)-1] {
+	case 'd':
+		unit = 1
+	case 'w':
+		unit = 7
+	default:
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil {
+		return 0, 0, false
+	}
+	return n, unit, true
+}
+
+func parseISOWeek(v string, loc *time.Location) (time.Time, error) {
+	year, week, ok := strings.Cut(v, "-w")
+	y, err1 := strconv.Atoi(year)
+	w, err2 := strconv.Atoi(week)
+	if !ok || len(year) != 4 || len(week) != 2 || err1 != nil || err2 != nil {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q (use YYYY-Www)", v)
+	}
+	jan4 := time.Date(y, time.January, 4, 0, 0, 0, 0, loc)
+	monday := jan4.AddDate(0, 0, -daysSinceMonday(jan4)+(w-1)*7)
+	if gy, gw := monday.ISOWeek(); gy != y || gw != w {
+		return time.Time{}, fmt.Errorf("invalid ISO week %q: %d has no week %d", v, y, w)
+	}
+	return monday, nil
+}
+
+func daysSinceMonday(t time.Time) int {
+	return (int(t.Weekday()) + 6) % 7
+}
+
+func parseLast(value string, now time.Time, datetime bool) (time.Time, time.Time, error) {
+	v := strings.ToLower(strings.TrimSpace(value))
+	if n, unit, ok := parseRelative("+" + v); ok && n > 0 {
+		start := startOfDay(now).AddDate(0, 0, -(n*unit - 1))
+		if datetime {
+			return start, now, nil
+		}
+		return start, startOfDay(now), nil
+	}
+	if datetime {
+		if d, err := time.ParseDuration(v); err == nil && d > 0 {
+			return now.Add(-d), now, nil
+		}
+		return time.Time{}, time.Time{}, fmt.Errorf("invalid --last %q (use Nd, Nw or a duration like 12h)", value)
+	}
+	return time.Time{}, time.Time{}, fmt.Errorf("invalid --last %q (use Nd or Nw)", value)
+}
+
+var timeFields = map[string][]string{
+	"daily_activity":     {"timestamp"},
+	"daily_readiness":    {"timestamp"},
+	"daily_sleep":        {"timestamp"},
+	"sleep":              {"bedtime_start", "bedtime_end", "timestamp"},
+	"session":            {"start_datetime", "end_datetime", "timestamp"},
+	"workout":            {"start_datetime", "end_datetime"},
+	"tag":                {"timestamp"},
+	"enhanced_tag":       {"start_time", "end_time"},
+	"rest_mode_period":   {"start_time", "end_time", "timestamp"},
+	"vo2_max":            {"timestamp"},
+	"ring_configuration": {"set_up_at"},
+	"heartrate":          {"timestamp"},
+}
+
+type timeLocalizer struct {
+	loc     *time.Location
+	pattern *regexp.Regexp
+}
+
+func newTimeLocalizer(loc *time.Location, fields []string) *timeLocalizer {
+	if loc == nil || len(fields) == 0 {
+		return nil
+	}
+	keys := make([]string, len(fields))
+	for i, f := range fields {
+		keys[i] = regexp.QuoteMeta(f)
+	}
+	pattern := regexp.MustCompile(`("(?:` + strings.Join(keys, "|") + `)"\s*:\s*)"(\d{4}-\d{2}-\d{2}T[^"]+)"`)
+	return &timeLocalizer{loc: loc, pattern: pattern}
+}
+
+func (z *timeLocalizer) apply(data []byte) []byte {
+	if z == nil {
+		return data
+	}
+	return z.pattern.ReplaceAllFunc(data, func(m []byte) []byte {
+		sub := z.pattern.FindSubmatch(m)
+		raw := string(sub[2])
+		t, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return m
+		}
+		layout := time.RFC3339
+		if strings.Contains(raw, ".") {
+			layout = time.RFC3339Nano
+		}
+		out := append([]byte{}, sub[1]...)
+		return append(out, `"`+t.In(z.loc).Format(layout)+`"`...)
+	})
+}
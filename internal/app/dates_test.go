@@ -0,0 +1,269 @@
+package app
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+func chicago(t *testing.T) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation("America/Chicago")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	return loc
+}
+
+func TestParseDateExpr(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, chicago(t))
+	tests := []struct {
+		expr       string
+		start, end string
+	}{
+		{"today", "2026-10-14", "2026-10-14"},
+		{"Yesterday", "2026-10-13", "2026-10-13"},
+		{"-7d", "2026-10-07", "2026-10-07"},
+		{"-2w", "2026-09-30", "2026-09-30"},
+		{"-0d", "2026-10-14", "2026-10-14"},
+		{"this-week", "2026-10-12", "2026-10-14"},
+		{"last-week", "2026-10-05", "2026-10-11"},
+		{"this-month", "2026-10-01", "2026-10-14"},
+		{"last-month", "2026-09-01", "2026-09-30"},
+		{"2026-10-01", "2026-10-01", "2026-10-01"},
+		{" 2026-10-01 ", "2026-10-01", "2026-10-01"},
+		{"2020-W53", "2020-12-28", "2021-01-03"},
+		{"2021-W01", "2021-01-04", "2021-01-10"},
+		{"2026-w01", "2025-12-29", "2026-01-04"},
+		{"2015-W53", "2015-12-28", "2016-01-03"},
+	}
+	for _, tt := range tests {
+		start, end, err := parseDateExpr(tt.expr, now)
+		if err != nil {
+			t.Errorf("parseDateExpr(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := formatDate(start); got != tt.start {
+			t.Errorf("parseDateExpr(%q) start = %s, want %s", tt.expr, got, tt.start)
+		}
+		if got := formatDate(end); got != tt.end {
+			t.Errorf("parseDateExpr(%q) end = %s, want %s", tt.expr, got, tt.end)
+		}
+		if start.Location() != now.Location() || start.Hour() != 0 {
+			t.Errorf("parseDateExpr(%q) start = %s, want local midnight", tt.expr, start)
+		}
+	}
+}
+
+func TestParseDateExprRejects(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, chicago(t))
+	for _, expr := range []string{"", "+3d", "-3m", "2021-W53", "2026-W00", "2026-W7", "26-W01", "2026-13-01", "tomorrow"} {
+		if _, _, err := parseDateExpr(expr, now); err == nil {
+			t.Errorf("parseDateExpr(%q) succeeded, want an error", expr)
+		}
+	}
+}
+
+func TestParseDateTimeExpr(t *testing.T) {
+	loc := chicago(t)
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, loc)
+	tests := []struct {
+		expr       string
+		start, end string
+	}{
+		{"2026-10-01T22:00", "2026-10-01T22:00:00-05:00", "2026-10-01T22:00:00-05:00"},
+		{"2026-10-01 22:00:30", "2026-10-01T22:00:30-05:00", "2026-10-01T22:00:30-05:00"},
+		{"2026-10-01T22:00:00Z", "2026-10-01T22:00:00Z", "2026-10-01T22:00:00Z"},
+		{"now", "2026-10-14T15:30:00-05:00", "2026-10-14T15:30:00-05:00"},
+		{"-6h", "2026-10-14T09:30:00-05:00", "2026-10-14T09:30:00-05:00"},
+		{"2026-10-01", "2026-10-01T00:00:00-05:00", "2026-10-02T00:00:00-05:00"},
+		{"2026-03-08", "2026-03-08T00:00:00-06:00", "2026-03-09T00:00:00-05:00"},
+		{"2026-11-01T01:30", "2026-11-01T01:30:00-05:00", "2026-11-01T01:30:00-05:00"},
+	}
+	for _, tt := range tests {
+		start, end, err := parseDateTimeExpr(tt.expr, now)
+		if err != nil {
+			t.Errorf("parseDateTimeExpr(%q): %v", tt.expr, err)
+			continue
+		}
+		if got := formatDateTime(start); got != tt.start {
+			t.Errorf("parseDateTimeExpr(%q) start = %s, want %s", tt.expr, got, tt.start)
+		}
+		if got := formatDateTime(end); got != tt.end {
+			t.Errorf("parseDateTimeExpr(%q) end = %s, want %s", tt.expr, got, tt.end)
+		}
+	}
+}
+
+func TestParseDateTimeExprRejectsDSTGap(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, chicago(t))
+	for _, expr := range []string{"2026-03-08T02:30", "2026-03-08T02:00", "2026-03-08 02:59:59"} {
+		if _, _, err := parseDateTimeExpr(expr, now); err == nil {
+			t.Errorf("parseDateTimeExpr(%q) succeeded inside the DST gap", expr)
+		}
+	}
+	if _, _, err := parseDateTimeExpr("2026-03-08T03:00", now); err != nil {
+		t.Errorf("parseDateTimeExpr after the DST gap: %v", err)
+	}
+}
+
+func TestBuildListQueryDates(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, chicago(t))
+	heartrate, _ := oura.LookupResource("heartrate")
+	dailySleep, _ := oura.LookupResource("daily_sleep")
+	tests := []struct {
+		name     string
+		resource oura.Resource
+		filter   listFilter
+		want     map[string]string
+	}{
+		{"heartrate day", heartrate, listFilter{startDate: "2026-10-01", endDate: "2026-10-01"},
+			map[string]string{"start_datetime": "2026-10-01T00:00:00-05:00", "end_datetime": "2026-10-02T00:00:00-05:00"}},
+		{"heartrate days across DST", heartrate, listFilter{startDate: "2026-03-07", endDate: "2026-03-08"},
+			map[string]string{"start_datetime": "2026-03-07T00:00:00-06:00", "end_datetime": "2026-03-09T00:00:00-05:00"}},
+		{"heartrate yesterday", heartrate, listFilter{startDate: "yesterday", endDate: "yesterday"},
+			map[string]string{"start_datetime": "2026-10-13T00:00:00-05:00", "end_datetime": "2026-10-14T00:00:00-05:00"}},
+		{"heartrate last duration", heartrate, listFilter{last: "12h"},
+			map[string]string{"start_datetime": "2026-10-14T03:30:00-05:00", "end_datetime": "2026-10-14T15:30:00-05:00"}},
+		{"heartrate last days", heartrate, listFilter{last: "2d"},
+			map[string]string{"start_datetime": "2026-10-13T00:00:00-05:00", "end_datetime": "2026-10-14T15:30:00-05:00"}},
+		{"daily last week", dailySleep, listFilter{last: "1w"},
+			map[string]string{"start_date": "2026-10-08", "end_date": "2026-10-14"}},
+		{"daily iso week", dailySleep, listFilter{startDate: "2020-W53", endDate: "2021-W01"},
+			map[string]string{"start_date": "2020-12-28", "end_date": "2021-01-10"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := buildListQuery(tt.resource, tt.filter, now)
+			if err != nil {
+				t.Fatalf("buildListQuery: %v", err)
+			}
+			if len(query) != len(tt.want) {
+				t.Errorf("query = %v, want %v", query, tt.want)
+			}
+			for key, want := range tt.want {
+				if got := query.Get(key); got != want {
+					t.Errorf("%s = %s, want %s", key, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBuildListQueryRejects(t *testing.T) {
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, chicago(t))
+	heartrate, _ := oura.LookupResource("heartrate")
+	dailySleep, _ := oura.LookupResource("daily_sleep")
+	tests := []struct {
+		name     string
+		resource oura.Resource
+		filter   listFilter
+	}{
+		{"daily duration", dailySleep, listFilter{last: "12h"}},
+		{"last with dates", dailySleep, listFilter{last: "7d", startDate: "today", endDate: "today"}},
+		{"mixed heartrate filters", heartrate, listFilter{startDate: "today", endDate: "today", startDateTime: "now", endDateTime: "now"}},
+		{"reversed days", heartrate, listFilter{startDate: "today", endDate: "yesterday"}},
+		{"dst gap", heartrate, listFilter{startDateTime: "2026-03-08T02:30", endDateTime: "2026-03-08T04:00"}},
+	}
+	for _, tt := range tests {
+		if _, err := buildListQuery(tt.resource, tt.filter, now); err == nil {
+			t.Errorf("%s: buildListQuery succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestLastWithLocalTimes(t *testing.T) {
+	loc := chicago(t)
+	now := time.Date(2026, 10, 14, 15, 30, 0, 0, loc)
+	start, end, err := parseLast("6h", now, true)
+	if err != nil {
+		t.Fatalf("parseLast: %v", err)
+	}
+	if !start.Equal(now.Add(-6*time.Hour)) || !end.Equal(now) {
+		t.Errorf("parseLast(6h) = %s..%s", start, end)
+	}
+
+	localizer := newTimeLocalizer(loc, []string{"timestamp"})
+	rec := []byte(`{"bpm":60,"source":"2026-10-14T12:00:00+00:00","timestamp":"2026-10-14T12:00:00+00:00","nested":{"timestamp":"2026-10-14T12:00:00.5Z"}}`)
+	want := `{"bpm":60,"source":"2026-10-14T12:00:00+00:00","timestamp":"2026-10-14T07:00:00-05:00","nested":{"timestamp":"2026-10-14T07:00:00.5-05:00"}}`
+	if got := string(localizer.apply(rec)); got != want {
+		t.Errorf("apply = %s\nwant    %s", got, want)
+	}
+	if got := newTimeLocalizer(nil, []string{"timestamp"}); got != nil {
+		t.Errorf("localizer without a location = %v, want nil", got)
+	}
+	if got := string(newTimeLocalizer(loc, nil).apply(rec)); got != string(rec) {
+		t.Errorf("localizer without fields changed the record: %s", got)
+	}
+}
+
+func loadSDKFixture(t *testing.T, key string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("..", "..", "pkg", "oura", "testdata", key+".json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func TestTimeFieldsMatchFixtures(t *testing.T) {
+	for _, r := range oura.Resources() {
+		t.Run(r.Key, func(t *testing.T) {
+			var doc any
+			if err := json.Unmarshal(loadSDKFixture(t, r.Key), &doc); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			found := map[string]bool{}
+			collectTimeFields(doc, found)
+			var got []string
+			for key := range found {
+				got = append(got, key)
+			}
+			want := append([]string{}, timeFields[r.Key]...)
+			sort.Strings(got)
+			sort.Strings(want)
+			if len(got) != len(want) || (len(got) > 0 && !reflect.DeepEqual(got, want)) {
+				t.Errorf("fixture datetime fields %v, TimeFields %v", got, want)
+			}
+		})
+	}
+}
+
+func collectTimeFields(v any, found map[string]bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if s, ok := child.(string); ok {
+				if _, err := time.Parse(time.RFC3339, s); err == nil {
+					found[key] = true
+				}
+			}
+			collectTimeFields(child, found)
+		}
+	case []any:
+		for _, child := range v {
+			collectTimeFields(child, found)
+		}
+	}
+}
+
+func TestResourceMetadataKeys(t *testing.T) {
+	for name, m := range map[string]map[string][]string{"tableColumns": tableColumns, "timeFields": timeFields} {
+		for key := range m {
+			if _, ok := oura.LookupResource(key); !ok {
+				t.Errorf("%s has unknown resource %q", name, key)
+			}
+		}
+	}
+	for _, r := range oura.Resources() {
+		if len(tableColumns[r.Key]) == 0 {
+			t.Errorf("tableColumns has no entry for %s", r.Key)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/devserver"
+	"github.com/mattjefferson/oura-cli/internal/output"
+)
+
+func runDev(printer *output.Printer, opts GlobalOptions, args []string) int {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		printer.Write(devUsage())
+		return 0
+	}
+	switch args[0] {
+	case "serve":
+		return runDevServe(printer, args[1:])
+	default:
+		printer.Errorf("unknown dev command: %s", args[0])
+		printer.WriteErr("\n")
+		printer.WriteErr(devUsage())
+		return 2
+	}
+}
+
+func runDevServe(printer *output.Printer, args []string) int {
+	fs := flag.NewFlagSet("dev serve", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var addr string
+	var pageSize int
+	var today string
+	var failStatus int
+	var failEvery int
+	var help bool
+
+	fs.StringVar(&addr, "addr", "127.0.0.1:8799", "listen address")
+	fs.IntVar(&pageSize, "page-size", 50, "documents per page")
+	fs.StringVar(&today, "today", "", "pin the current day")
+	fs.IntVar(&failStatus, "fail-status", 0, "status to inject")
+	fs.IntVar(&failEvery, "fail-every", 0, "inject failure every n requests")
+	fs.BoolVar(&help, "help", false, "show help")
+	fs.BoolVar(&help, "h", false, "show help")
+
+	if err := parseFlags(fs, args); err != nil {
+		printer.Errorf("flag error: %v", err)
+		printer.WriteErr("\n")
+		printer.WriteErr(devServeUsage())
+		return 2
+	}
+	if help {
+		printer.Write(devServeUsage())
+		return 0
+	}
+	if failStatus != 0 && (failStatus < 400 || failStatus > 599) {
+		printer.Errorf("fail-status must be a 4xx or 5xx code")
+		return 2
+	}
+
+	serverOpts := devserver.Options{
+		PageSize:   pageSize,
+		FailStatus: failStatus,
+		FailEvery:  failEvery,
+		Logf:       printer.Debugf,
+	}
+	if today != "" {
+		t, err := parseDate(today)
+		if err != nil {
+			printer.Errorf("invalid --today: %v", err)
+			return 2
+		}
+		serverOpts.Today = t
+	}
+
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		printer.Errorf("listen failed: %v", err)
+		return 1
+	}
+	srv := &http.Server{Handler: devserver.New(serverOpts).Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	base := "http://" + ln.Addr().String()
+	printer.Infof("fake oura api listening on %s", base)
+	printer.Infof("try: OURA_API_BASE_URL=%s OURA_ACCESS_TOKEN=fake oura list daily_sleep", base)
+	if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		printer.Errorf("server failed: %v", err)
+		return 1
+	}
+	return 0
+}
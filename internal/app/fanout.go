@@ -0,0 +1,192 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+type account struct {
+	Name string
+	Path string
+}
+
+func expandConfigs(list string) ([]account, error) {
+	var paths []string
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		if !strings.ContainsAny(item, "*?[") {
+			paths = append(paths, item)
+			continue
+		}
+		matches, err := filepath.Glob(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", item, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no configs match %q", item)
+		}
+		sort.Strings(matches)
+		paths = append(paths, matches...)
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no configs given")
+	}
+
+	var out []account
+	seenPath := map[string]bool{}
+	seenName := map[string]bool{}
+	for _, path := range paths {
+		if seenPath[path] {
+			continue
+		}
+		seenPath[path] = true
+		name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		if seenName[name] {
+			name = path
+		}
+		seenName[name] = true
+		out = append(out, account{Name: name, Path: path})
+	}
+	return out, nil
+}
+
+func tagRecord(rec json.RawMessage, name string) json.RawMessage {
+	trimmed := bytes.TrimSpace(rec)
+	if len(trimmed) < 2 || trimmed[0] != '{' {
+		return rec
+	}
+	key, _ := json.Marshal(name)
+	var buf bytes.Buffer
+	buf.WriteString(`{"account":`)
+	buf.Write(key)
+	body := bytes.TrimSpace(trimmed[1:])
+	if body[0] != '}' {
+		buf.WriteByte(',')
+	}
+	buf.Write(body)
+	return buf.Bytes()
+}
+
+type fanOutList struct {
+	accounts  []account
+	parallel  int
+	resource  oura.Resource
+	sandbox   bool
+	path      string
+	query     url.Values
+	maxPages  int
+	localizer *timeLocalizer
+}
+
+func (f fanOutList) run(printer *output.Printer, opts GlobalOptions, fn func(index int, records []json.RawMessage) error) int {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	codes := make([]int, len(f.accounts))
+	sem := make(chan struct{}, f.parallel)
+	for i, acct := range f.accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			p := printer.WithPrefix(acct.Name + ": ")
+			accountOpts := opts
+			accountOpts.ConfigPath = acct.Path
+			client, code, err := loadClient(accountOpts, p, f.resource, f.sandbox)
+			if err != nil {
+				p.Errorf("auth required: %v", err)
+				codes[i] = code
+				return
+			}
+			codes[i] = fetchPages(p, client, f.path, f.query, f.maxPages, f.localizer, func(page listPage) error {
+				records := make([]json.RawMessage, len(page.Data))
+				for j, rec := range page.Data {
+					records[j] = tagRecord(rec, acct.Name)
+				}
+				mu.Lock()
+				defer mu.Unlock()
+				return fn(i, records)
+			})
+		}()
+	}
+	wg.Wait()
+
+	exit, failed := 0, 0
+	for _, code := range codes {
+		if code != 0 {
+			failed++
+			exit = max(exit, code)
+		}
+	}
+	if failed > 0 {
+		printer.Errorf("%d of %d accounts failed", failed, len(f.accounts))
+	}
+	return exit
+}
+
+func runListFanOut(printer *output.Printer, opts GlobalOptions, f fanOutList, resource oura.Resource, format string, fields []string) int {
+	switch format {
+	case "ndjson":
+		return f.run(printer, opts, func(_ int, records []json.RawMessage) error {
+			return printer.PrintNDJSON(records)
+		})
+	case "csv", "tsv":
+		comma := ','
+		if format == "tsv" {
+			comma = '\t'
+		}
+		w := printer.NewDelimitedWriter(comma, fields)
+		code := f.run(printer, opts, func(_ int, records []json.RawMessage) error {
+			return w.WriteRecords(records)
+		})
+		if err := w.Close(); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return max(code, 1)
+		}
+		return code
+	}
+
+	perAccount := make([][]json.RawMessage, len(f.accounts))
+	code := f.run(printer, opts, func(i int, records []json.RawMessage) error {
+		perAccount[i] = append(perAccount[i], records...)
+		return nil
+	})
+	merged := []json.RawMessage{}
+	for _, records := range perAccount {
+		merged = append(merged, records...)
+	}
+
+	if format == "table" {
+		if columns := tableColumns[resource.Key]; len(fields) == 0 && len(columns) > 0 {
+			fields = append([]string{"account"}, columns...)
+		}
+		if err := printTable(printer, resource, fields, merged); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return max(code, 1)
+		}
+		return code
+	}
+	b, err := json.Marshal(listPage{Data: merged})
+	if err != nil {
+		printer.Errorf("json encode failed: %v", err)
+		return max(code, 1)
+	}
+	if err := printer.PrintJSON(b); err != nil {
+		printer.Errorf("output failed: %v", err)
+		return max(code, 1)
+	}
+	return code
+}
@@ -0,0 +1,62 @@
+package app
+
+import (
+	"encoding/json"
+
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+var tableColumns = map[string][]string{
+	"personal_info":            {"id", "age", "weight", "height", "biological_sex", "email"},
+	"daily_activity":           {"day", "score", "steps", "active_calories", "total_calories"},
+	"daily_cardiovascular_age": {"day", "vascular_age"},
+	"daily_readiness":          {"day", "score", "temperature_deviation", "contributors.resting_heart_rate", "contributors.hrv_balance"},
+	"daily_resilience":         {"day", "level", "contributors.sleep_recovery", "contributors.daytime_recovery", "contributors.stress"},
+	"daily_sleep":              {"day", "score", "contributors.total_sleep", "contributors.deep_sleep", "contributors.rem_sleep", "contributors.efficiency"},
+	"daily_spo2":               {"day", "spo2_percentage.average", "breathing_disturbance_index"},
+	"daily_stress":             {"day", "stress_high", "recovery_high", "day_summary"},
+	"sleep":                    {"day", "type", "bedtime_start", "bedtime_end", "total_sleep_duration", "efficiency", "average_hrv"},
+	"sleep_time":               {"day", "recommendation", "status"},
+	"session":                  {"day", "type", "start_datetime", "end_datetime", "mood"},
+	"workout":                  {"day", "start_datetime", "end_datetime", "activity", "calories", "intensity"},
+	"tag":                      {"day", "timestamp", "text", "tags"},
+	"enhanced_tag":             {"start_day", "start_time", "tag_type_code", "comment"},
+	"rest_mode_period":         {"start_day", "end_day"},
+	"vo2_max":                  {"day", "vo2_max"},
+	"ring_configuration":       {"id", "color", "design", "firmware_version", "hardware_type", "size"},
+	"heartrate":                {"timestamp", "bpm", "source"},
+}
+
+func resolveFormat(printer *output.Printer, format string) string {
+	if format != "" {
+		return format
+	}
+	if printer.Pretty {
+		return "table"
+	}
+	return "json"
+}
+
+func printTable(printer *output.Printer, resource oura.Resource, fields []string, records []json.RawMessage) error {
+	columns := fields
+	if len(columns) == 0 {
+		columns = tableColumns[resource.Key]
+	}
+	rows := make([][]string, 0, len(records))
+	for _, rec := range records {
+		keys, values, err := output.Flatten(rec)
+		if err != nil {
+			return err
+		}
+		if len(columns) == 0 {
+			columns = keys
+		}
+		row := make([]string, len(columns))
+		for i, c := range columns {
+			row[i] = values[c]
+		}
+		rows = append(rows, row)
+	}
+	return printer.PrintTable(columns, rows)
+}
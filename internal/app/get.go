@@ -2,11 +2,12 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"flag"
 	"io"
 
-	"github.com/mattjefferson/oura-cli/internal/oura"
 	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
 )
 
 func runGet(printer *output.Printer, opts GlobalOptions, args []string) int {
@@ -14,13 +15,17 @@ func runGet(printer *output.Printer, opts GlobalOptions, args []string) int {
 	fs.SetOutput(io.Discard)
 
 	var sandbox bool
+	var format string
+	var source string
 	var help bool
 
 	fs.BoolVar(&sandbox, "sandbox", false, "use sandbox")
+	fs.StringVar(&format, "format", "", "output format")
+	fs.StringVar(&source, "source", "", "answer from saved responses in dir")
 	fs.BoolVar(&help, "help", false, "show help")
 	fs.BoolVar(&help, "h", false, "show help")
 
-	if err := fs.Parse(args); err != nil {
+	if err := parseFlags(fs, args); err != nil {
 		printer.Errorf("flag error: %v", err)
 		printer.WriteErr("\n")
 		printer.WriteErr(getUsage())
@@ -49,6 +54,12 @@ func runGet(printer *output.Printer, opts GlobalOptions, args []string) int {
 		return 2
 	}
 
+	format = resolveFormat(printer, format)
+	if format != "json" && format != "table" {
+		printer.Errorf("unknown format: %s", format)
+		return 2
+	}
+
 	documentID := ""
 	if resource.Key != "personal_info" {
 		if len(rest) < 2 {
@@ -61,18 +72,17 @@ func runGet(printer *output.Printer, opts GlobalOptions, args []string) int {
 		return 2
 	}
 
-	return fetchResource(printer, opts, resource, documentID, sandbox)
+	return fetchResource(printer, opts, resource, documentID, sandbox, format, source)
 }
 
 func runWhoami(printer *output.Printer, opts GlobalOptions) int {
 	resource, _ := oura.LookupResource("personal_info")
-	return fetchResource(printer, opts, resource, "", false)
+	return fetchResource(printer, opts, resource, "", false, resolveFormat(printer, ""), "")
 }
 
-func fetchResource(printer *output.Printer, opts GlobalOptions, resource oura.Resource, documentID string, sandbox bool) int {
-	client, code, err := loadClient(opts, printer)
+func fetchResource(printer *output.Printer, opts GlobalOptions, resource oura.Resource, documentID string, sandbox bool, format, source string) int {
+	client, code, err := listClient(opts, printer, source, nil, resource, sandbox)
 	if err != nil {
-		printer.Errorf("auth required: %v", err)
 		return code
 	}
 
@@ -83,18 +93,22 @@ func fetchResource(printer *output.Printer, opts GlobalOptions, resource oura.Re
 		path = oura.BuildDocumentPath(sandbox, resource.PathSegment, documentID)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
-	defer cancel()
-
-	resp, err := client.Get(ctx, path, nil)
+	resp, err := client.Get(context.Background(), path, nil)
 	if err != nil {
 		printer.Errorf("request failed: %v", err)
 		return 4
 	}
 	if resp.Status >= 400 {
-		printer.Errorf("api error (%d): %s", resp.Status, apiErrorMessage(resp.Body))
+		printer.Errorf("api error (%d): %s", resp.Status, oura.ErrorMessage(resp.Body))
 		return exitCodeForStatus(resp.Status)
 	}
+	if format == "table" {
+		if err := printTable(printer, resource, nil, []json.RawMessage{resp.Body}); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
 	if err := printer.PrintJSON(resp.Body); err != nil {
 		printer.Errorf("output failed: %v", err)
 		return 1
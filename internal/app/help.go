@@ -28,6 +28,18 @@ func runHelp(printer *output.Printer, args []string) int {
 		case "whoami":
 			printer.Write(whoamiUsage())
 			return 0
+		case "profile":
+			printer.Write(profileUsage())
+			return 0
+		case "config":
+			printer.Write(configUsage())
+			return 0
+		case "sync":
+			printer.Write(syncUsage())
+			return 0
+		case "dev":
+			printer.Write(devUsage())
+			return 0
 		default:
 			printer.Errorf("unknown command: %s", args[0])
 			printer.WriteErr("\n")
@@ -46,6 +58,15 @@ func runHelp(printer *output.Printer, args []string) int {
 		case "logout":
 			printer.Write(authLogoutUsage())
 			return 0
+		case "refresh":
+			printer.Write(authRefreshUsage())
+			return 0
+		case "token":
+			printer.Write(authTokenUsage())
+			return 0
+		case "scopes":
+			printer.Write(authScopesUsage())
+			return 0
 		default:
 			printer.Errorf("unknown auth command: %s", args[1])
 			printer.WriteErr("\n")
@@ -54,6 +75,16 @@ func runHelp(printer *output.Printer, args []string) int {
 		}
 	}
 
+	if len(args) >= 2 && args[0] == "profile" && args[1] == "add" {
+		printer.Write(profileAddUsage())
+		return 0
+	}
+
+	if len(args) >= 2 && args[0] == "dev" && args[1] == "serve" {
+		printer.Write(devServeUsage())
+		return 0
+	}
+
 	printer.Errorf("unknown help target: %s", strings.Join(args, " "))
 	printer.WriteErr("\n")
 	printer.WriteErr(rootUsage())
@@ -65,11 +96,15 @@ func rootUsage() string {
   oura [global flags] <command> [args]
 
 Commands:
-  auth       OAuth2 login, status, logout
+  auth       OAuth2 login, status, logout, refresh, token, scopes
   list       List a resource collection
   get        Fetch a resource by id
   whoami     Fetch personal info
   resources  List available resources
+  profile    Manage named profiles (one per Oura account)
+  config     Encrypt or decrypt the config file
+  sync       Incrementally copy resources into a local store
+  dev        Local development tools (fake API server)
   help       Show help for a command
 
 Global flags:
@@ -78,13 +113,31 @@ Global flags:
   -q, --quiet          Suppress non-data output
   -v, --verbose        Verbose logging
   --json               Compact JSON output
+  --no-color           Disable colored output (also NO_COLOR)
   --no-input           Disable prompts
   --config <path>      Config path (default ~/.config/oura/config.json)
+  --profile <name>     Config profile (env: OURA_PROFILE; default: current profile)
+  --passphrase-file <path>
+                       Passphrase for an encrypted config
+                       (env: OURA_CONFIG_PASSPHRASE, OURA_CONFIG_PASSPHRASE_FILE)
   --timeout <dur>      HTTP timeout (default 30s)
+  --base-url <url>     API base URL (env: OURA_API_BASE_URL)
+  --retries <n>        Retries for 429/5xx and network errors (default 2)
+  --max-backoff <dur>  Maximum wait between retries (default 30s)
+  --rate-limit <n>     Max requests per minute, shared across processes
+                       (default: rate_limit_per_minute from config; 0 disables)
+  --refresh-skew <dur> Refresh tokens this long before they expire
+                       (default: refresh_skew_seconds from config, else 5m)
+  --tz <zone>          Timezone for date expressions, e.g. America/Chicago
+                       (env: OURA_TZ; default: timezone from config, else local)
+  --no-cache           Bypass the on-disk response cache
+  --cache-ttl <dur>    Cache responses for this long instead of per-resource TTLs
 
 Examples:
   oura auth login --scopes daily heartrate
   oura list sleep --start-date 2024-01-01 --end-date 2024-01-07
+  oura list daily_sleep --last 7d
+  oura list heartrate --all --start-datetime 2024-01-01T00:00:00Z --end-datetime 2024-01-08T00:00:00Z
   oura get daily_activity <document_id>
   oura whoami
 `
@@ -95,6 +148,9 @@ func authUsage() string {
   oura auth login [flags]
   oura auth status
   oura auth logout
+  oura auth refresh
+  oura auth token
+  oura auth scopes
 
 Run:
   oura help auth login
@@ -111,10 +167,19 @@ Flags:
   --redirect-uri <uri>       OAuth redirect URI (env: OURA_REDIRECT_URI)
   --scopes <list>            Scopes (space/comma-separated; default: daily)
   --no-open                  Do not open a browser
-  --paste                    Print URL and prompt for the code
+  --paste                    Print URL and prompt for the redirect URL or code
+  --remote                   Headless login: print the URL and an SSH port-forward
+                             hint, then take either the callback or a pasted
+                             redirect URL/code
+  --bind <addr>              Callback listen address (default: redirect URI host:port)
+  --no-pkce                  Do not send a PKCE code challenge (also disable_pkce in config)
+  --public-client            Log in without a client secret (saved as public_client;
+                             needs PKCE)
 
 Notes:
   Redirect URI must match your Oura app settings.
+  PKCE (S256) is used by default. The client secret is required unless the
+  profile is a public client.
 `
 }
 
@@ -126,7 +191,93 @@ func authStatusUsage() string {
 
 func authLogoutUsage() string {
 	return `Usage:
-  oura auth logout
+  oura auth logout [flags]
+
+Flags:
+  --local-only   Remove tokens locally without revoking them with Oura
+
+Notes:
+  Revokes the access and refresh tokens, removes them from the config and
+  clears the profile's response cache. If a revoke fails, the tokens are kept
+  so you can retry. The sync store is left in place. A token supplied through
+  OURA_ACCESS_TOKEN or OURA_REFRESH_TOKEN is neither revoked nor removed.
+`
+}
+
+func profileUsage() string {
+	return `Usage:
+  oura profile list
+  oura profile add <name> [flags]
+  oura profile remove <name>
+  oura profile use <name>
+
+Notes:
+  Each profile keeps its own OAuth client, tokens, cache and sync store.
+  Select one per command with --profile or OURA_PROFILE.
+`
+}
+
+func profileAddUsage() string {
+	return `Usage:
+  oura profile add <name> [flags]
+
+Flags:
+  --client-id <id>       OAuth client id
+  --redirect-uri <uri>   OAuth redirect URI
+  --scopes <list>        Scopes (space/comma-separated)
+  --use                  Make the new profile current
+
+Then log in with:
+  oura --profile <name> auth login
+`
+}
+
+func configUsage() string {
+	return `Usage:
+  oura config encrypt
+  oura config decrypt
+  oura config set timezone <zone>
+
+Notes:
+  encrypt seals the whole config file (all profiles) with AES-256-GCM using a
+  key derived from a passphrase. Commands then need the passphrase from
+  OURA_CONFIG_PASSPHRASE, --passphrase-file / OURA_CONFIG_PASSPHRASE_FILE,
+  or a terminal prompt.
+  decrypt writes the file back as plain JSON.
+  set timezone stores an IANA zone (e.g. America/Chicago) for the active
+  profile, used for date expressions; Local clears it.
+`
+}
+
+func authRefreshUsage() string {
+	return `Usage:
+  oura auth refresh
+
+Notes:
+  Exchanges the refresh token for a new access token now, saves it and
+  prints the new expiry. Exits 1 if the new token could not be saved and
+  3 if the refresh itself was rejected.
+`
+}
+
+func authTokenUsage() string {
+	return `Usage:
+  oura auth token
+
+Notes:
+  Prints a valid access token to stdout, refreshing it first if it is
+  about to expire. For example:
+    curl -H "Authorization: Bearer $(oura auth token)" https://api.ouraring.com/v2/usercollection/personal_info
+`
+}
+
+func authScopesUsage() string {
+	return `Usage:
+  oura auth scopes
+
+Notes:
+  Shows the scopes granted to the current login and, for each resource,
+  the scopes it needs. list, get and sync check these before calling the API.
 `
 }
 
@@ -135,12 +286,37 @@ func listUsage() string {
   oura list <resource> [flags]
 
 Flags:
-  --start-date <YYYY-MM-DD>
-  --end-date <YYYY-MM-DD>
-  --start-datetime <RFC3339>
-  --end-datetime <RFC3339>
+  --start-date <date>
+  --end-date <date>
+  --start-datetime <datetime>
+  --end-datetime <datetime>
+  --last <n>d|<n>w           Last n days or weeks up to today (sets both ends)
+  --local-times              Render the resource's datetime fields in --tz
   --next-token <token>
+  --all                      Follow next_token and merge all pages
+  --max-pages <n>            Stop after n pages (implies --all)
+  --ndjson                   Print one record per line as pages arrive
+                             (--format ndjson; conflicts with other formats)
+  --format <fmt>             table, json, ndjson, csv or tsv
+                             (default: table on a terminal, json otherwise)
+  --fields <list>            Comma-separated dotted fields for csv/tsv/table
+  --source <dir>             Answer from saved responses instead of the API
+  --configs <list>           Query several config files at once (comma-separated
+                             paths or globs); records gain an "account" field
+  --parallel <n>             Accounts queried concurrently with --configs (default 4)
   --sandbox
+
+Notes:
+  Dates are YYYY-MM-DD, today, yesterday, -7d, -2w, this-week, last-week,
+  this-month, last-month or an ISO week (2026-W41), resolved in --tz.
+  Datetimes are RFC3339, wall-clock times in --tz (2026-10-01T22:00), now, -6h
+  or a date expression; heartrate also takes --last 12h. --start-date/--end-date
+  on heartrate cover local midnight to midnight.
+  csv/tsv/table flatten nested objects into dotted columns (contributors.deep_sleep).
+  Tables use per-resource default columns; csv/tsv default to the first record's fields.
+  --source reads <dir>/<resource>.json and <dir>/<resource>/**/*.json (also .ndjson);
+  files may hold list pages, arrays, single documents or NDJSON. An oura sync
+  store directory works as a source.
 `
 }
 
@@ -149,6 +325,8 @@ func getUsage() string {
   oura get <resource> [document_id] [flags]
 
 Flags:
+  --format <fmt>   table or json (default: table on a terminal, json otherwise)
+  --source <dir>   Answer from saved responses instead of the API
   --sandbox
 
 Notes:
@@ -168,3 +346,49 @@ func whoamiUsage() string {
   oura whoami
 `
 }
+
+func syncUsage() string {
+	return `Usage:
+  oura sync [flags]
+
+Flags:
+  --resources <list>   Resources to sync (default: every listable resource)
+  --since <date>       First day for an initial sync (default: 30 days ago);
+                       accepts the date expressions of oura list
+  --window <days>      Days re-fetched before the last synced day (default 3)
+  --store <dir>        Store directory (default: store/ next to the config file)
+  --sandbox
+
+Notes:
+  Documents are stored as one JSON array per resource and day
+  (<store>/<resource>/<YYYY-MM-DD>.json); state.json records the last synced day.
+`
+}
+
+func devUsage() string {
+	return `Usage:
+  oura dev serve [flags]
+
+Run:
+  oura help dev serve
+`
+}
+
+func devServeUsage() string {
+	return `Usage:
+  oura dev serve [flags]
+
+Flags:
+  --addr <host:port>     Listen address (default 127.0.0.1:8799)
+  --page-size <n>        Documents per page (default 50)
+  --today <YYYY-MM-DD>   Pin the current day for deterministic ranges
+  --fail-status <code>   Status to inject (4xx/5xx)
+  --fail-every <n>       Inject --fail-status on every nth API request
+
+Notes:
+  Serves /v2/usercollection/*, /oauth/authorize and /oauth/token with
+  deterministic synthetic data. Any bearer token is accepted.
+  Arm failures at runtime: curl "http://127.0.0.1:8799/_fake/fail?status=503&count=2"
+  Point the CLI at it with --base-url or OURA_API_BASE_URL.
+`
+}
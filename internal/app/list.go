@@ -2,13 +2,15 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"flag"
 	"io"
 	"net/url"
+	"time"
 
-	"github.com/mattjefferson/oura-cli/internal/oura"
 	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
 )
 
 func runList(printer *output.Printer, opts GlobalOptions, args []string) int {
@@ -19,20 +21,40 @@ func runList(printer *output.Printer, opts GlobalOptions, args []string) int {
 	var endDate string
 	var startDateTime string
 	var endDateTime string
+	var last string
+	var localTimes bool
 	var nextToken string
+	var all bool
+	var maxPages int
+	var ndjson bool
+	var format string
+	var fields string
 	var sandbox bool
+	var source string
+	var configs string
+	var parallel int
 	var help bool
 
 	fs.StringVar(&startDate, "start-date", "", "start date")
 	fs.StringVar(&endDate, "end-date", "", "end date")
 	fs.StringVar(&startDateTime, "start-datetime", "", "start datetime")
 	fs.StringVar(&endDateTime, "end-datetime", "", "end datetime")
+	fs.StringVar(&last, "last", "", "date range ending now, like 30d")
+	fs.BoolVar(&localTimes, "local-times", false, "render timestamps in the --tz timezone")
 	fs.StringVar(&nextToken, "next-token", "", "next token")
+	fs.BoolVar(&all, "all", false, "follow next_token until exhausted")
+	fs.IntVar(&maxPages, "max-pages", 0, "maximum pages to fetch")
+	fs.BoolVar(&ndjson, "ndjson", false, "one json document per line")
+	fs.StringVar(&format, "format", "", "output format")
+	fs.StringVar(&fields, "fields", "", "fields for csv/tsv/table output")
 	fs.BoolVar(&sandbox, "sandbox", false, "use sandbox")
+	fs.StringVar(&source, "source", "", "answer from saved responses in dir")
+	fs.StringVar(&configs, "configs", "", "config files to query concurrently")
+	fs.IntVar(&parallel, "parallel", 4, "accounts to query at once with --configs")
 	fs.BoolVar(&help, "help", false, "show help")
 	fs.BoolVar(&help, "h", false, "show help")
 
-	if err := fs.Parse(args); err != nil {
+	if err := parseFlags(fs, args); err != nil {
 		printer.Errorf("flag error: %v", err)
 		printer.WriteErr("\n")
 		printer.WriteErr(listUsage())
@@ -61,70 +83,294 @@ func runList(printer *output.Printer, opts GlobalOptions, args []string) int {
 		return 2
 	}
 
-	query, err := buildListQuery(resource, startDate, endDate, startDateTime, endDateTime, nextToken)
+	if maxPages < 0 {
+		printer.Errorf("max-pages must be >= 0")
+		return 2
+	}
+	if maxPages > 0 {
+		all = true
+	}
+	if ndjson {
+		if format != "" && format != "ndjson" {
+			printer.Errorf("--ndjson conflicts with --format %s", format)
+			return 2
+		}
+		format = "ndjson"
+	}
+	format = resolveFormat(printer, format)
+	switch format {
+	case "json", "ndjson", "csv", "tsv", "table":
+	default:
+		printer.Errorf("unknown format: %s", format)
+		return 2
+	}
+	if fields != "" && (format == "json" || format == "ndjson") {
+		printer.Errorf("--fields requires --format csv, tsv or table")
+		return 2
+	}
+
+	filter := listFilter{
+		startDate:     startDate,
+		endDate:       endDate,
+		startDateTime: startDateTime,
+		endDateTime:   endDateTime,
+		last:          last,
+		nextToken:     nextToken,
+	}
+	var loaded *loadedConfig
+	timezone := ""
+	if source == "" && configs == "" {
+		l, err := loadConfig(opts, printer)
+		if err != nil {
+			printer.Errorf("auth required: %v", err)
+			return 1
+		}
+		loaded = &l
+		timezone = l.Cfg.Timezone
+	}
+	var now time.Time
+	var localizer *timeLocalizer
+	if filter.hasDates() || localTimes {
+		loc, err := userLocation(opts, timezone)
+		if err != nil {
+			printer.Errorf("%v", err)
+			return 2
+		}
+		now = time.Now().In(loc)
+		if localTimes {
+			localizer = newTimeLocalizer(loc, timeFields[resource.Key])
+		}
+	}
+	query, err := buildListQuery(resource, filter, now)
 	if err != nil {
 		printer.Errorf("invalid query: %v", err)
 		return 2
 	}
 
-	client, code, err := loadClient(opts, printer)
+	path := oura.BuildPath(sandbox, resource.PathSegment)
+	if format != "json" && !all {
+		maxPages = 1
+	}
+
+	if configs != "" {
+		if source != "" || nextToken != "" {
+			printer.Errorf("--configs cannot be combined with --source or --next-token")
+			return 2
+		}
+		if parallel < 1 {
+			printer.Errorf("parallel must be >= 1")
+			return 2
+		}
+		accounts, err := expandConfigs(configs)
+		if err != nil {
+			printer.Errorf("invalid --configs: %v", err)
+			return 2
+		}
+		if !all {
+			maxPages = 1
+		}
+		f := fanOutList{accounts: accounts, parallel: parallel, resource: resource, sandbox: sandbox, path: path, query: query, maxPages: maxPages, localizer: localizer}
+		return runListFanOut(printer, opts, f, resource, format, output.ParseFields(fields))
+	}
+
+	client, code, err := listClient(opts, printer, source, loaded, resource, sandbox)
 	if err != nil {
-		printer.Errorf("auth required: %v", err)
 		return code
 	}
+	switch format {
+	case "ndjson":
+		return fetchPages(printer, client, path, query, maxPages, localizer, func(page listPage) error {
+			return printer.PrintNDJSON(page.Data)
+		})
+	case "csv", "tsv":
+		comma := ','
+		if format == "tsv" {
+			comma = '\t'
+		}
+		w := printer.NewDelimitedWriter(comma, output.ParseFields(fields))
+		code := fetchPages(printer, client, path, query, maxPages, localizer, func(page listPage) error {
+			return w.WriteRecords(page.Data)
+		})
+		if code != 0 {
+			return code
+		}
+		if err := w.Close(); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	case "table":
+		var records []json.RawMessage
+		code := fetchPages(printer, client, path, query, maxPages, localizer, func(page listPage) error {
+			records = append(records, page.Data...)
+			return nil
+		})
+		if code != 0 {
+			return code
+		}
+		if err := printTable(printer, resource, output.ParseFields(fields), records); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
+	if all {
+		return listAll(printer, client, path, query, maxPages, localizer)
+	}
 
-	path := oura.BuildPath(sandbox, resource.PathSegment)
-	ctx, cancel := context.WithTimeout(context.Background(), opts.Timeout)
-	defer cancel()
-
-	resp, err := client.Get(ctx, path, query)
+	resp, err := client.Get(context.Background(), path, query)
 	if err != nil {
 		printer.Errorf("request failed: %v", err)
 		return 4
 	}
 	if resp.Status >= 400 {
-		printer.Errorf("api error (%d): %s", resp.Status, apiErrorMessage(resp.Body))
+		printer.Errorf("api error (%d): %s", resp.Status, oura.ErrorMessage(resp.Body))
 		return exitCodeForStatus(resp.Status)
 	}
-	if err := printer.PrintJSON(resp.Body); err != nil {
+	if err := printer.PrintJSON(localizer.apply(resp.Body)); err != nil {
 		printer.Errorf("output failed: %v", err)
 		return 1
 	}
 	return 0
 }
 
-func buildListQuery(resource oura.Resource, startDate, endDate, startDateTime, endDateTime, nextToken string) (url.Values, error) {
+func listClient(opts GlobalOptions, printer *output.Printer, source string, loaded *loadedConfig, resource oura.Resource, sandbox bool) (*oura.Client, int, error) {
+	if source != "" {
+		client, code, err := loadSourceClient(source, printer)
+		if err != nil {
+			printer.Errorf("source unavailable: %v", err)
+		}
+		return client, code, err
+	}
+	var client *oura.Client
+	var code int
+	var err error
+	if loaded != nil {
+		client, code, err = resourceClient(loaded, opts, printer, resource, sandbox)
+	} else {
+		client, code, err = loadClient(opts, printer, resource, sandbox)
+	}
+	if err != nil {
+		printer.Errorf("auth required: %v", err)
+	}
+	return client, code, err
+}
+
+type listPage = oura.ListResponse[json.RawMessage]
+
+func listAll(printer *output.Printer, client *oura.Client, path string, query url.Values, maxPages int, localizer *timeLocalizer) int {
+	merged := listPage{Data: []json.RawMessage{}}
+	code := fetchPages(printer, client, path, query, maxPages, localizer, func(page listPage) error {
+		merged.Data = append(merged.Data, page.Data...)
+		merged.NextToken = page.NextToken
+		return nil
+	})
+	if code != 0 {
+		return code
+	}
+
+	b, err := json.Marshal(merged)
+	if err != nil {
+		printer.Errorf("json encode failed: %v", err)
+		return 1
+	}
+	if err := printer.PrintJSON(b); err != nil {
+		printer.Errorf("output failed: %v", err)
+		return 1
+	}
+	return 0
+}
+
+func fetchPages(printer *output.Printer, client *oura.Client, path string, query url.Values, maxPages int, localizer *timeLocalizer, fn func(listPage) error) int {
+	ctx := context.Background()
+	pages := 0
+	for page, err := range oura.Pages[json.RawMessage](ctx, client, path, query) {
+		if err != nil {
+			return reportRequestError(printer, err)
+		}
+		pages++
+		printer.Debugf("page %d: %d records", pages, len(page.Data))
+		if localizer != nil {
+			for i, rec := range page.Data {
+				page.Data[i] = localizer.apply(rec)
+			}
+		}
+		if err := fn(page); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		if page.NextToken != nil && maxPages > 0 && pages >= maxPages {
+			printer.Infof("stopped after %d pages; more data available (next_token %s)", pages, *page.NextToken)
+			return 0
+		}
+	}
+	return 0
+}
+
+func reportRequestError(printer *output.Printer, err error) int {
+	var apiErr *oura.APIError
+	if errors.As(err, &apiErr) {
+		printer.Errorf("%v", apiErr)
+		return exitCodeForStatus(apiErr.Status)
+	}
+	printer.Errorf("request failed: %v", err)
+	return 4
+}
+
+type listFilter struct {
+	startDate     string
+	endDate       string
+	startDateTime string
+	endDateTime   string
+	last          string
+	nextToken     string
+}
+
+func (f listFilter) hasDates() bool {
+	return f.startDate != "" || f.endDate != "" || f.startDateTime != "" || f.endDateTime != "" || f.last != ""
+}
+
+func buildListQuery(resource oura.Resource, f listFilter, now time.Time) (url.Values, error) {
 	params := map[string]string{}
-	if nextToken != "" {
-		params["next_token"] = nextToken
+	if f.nextToken != "" {
+		params["next_token"] = f.nextToken
 	}
 
 	switch resource.Query {
 	case oura.QueryNone:
-		if startDate != "" || endDate != "" || startDateTime != "" || endDateTime != "" {
+		if f.hasDates() {
 			return nil, errors.New("resource does not accept date filters")
 		}
 	case oura.QueryNextTokenOnly:
-		if startDate != "" || endDate != "" || startDateTime != "" || endDateTime != "" {
+		if f.hasDates() {
 			return nil, errors.New("resource only accepts next_token")
 		}
 	case oura.QueryDate:
-		if startDateTime != "" || endDateTime != "" {
+		if f.startDateTime != "" || f.endDateTime != "" {
 			return nil, errors.New("use --start-date/--end-date for this resource")
 		}
-		if startDate == "" && endDate == "" {
-			break
-		}
-		if startDate == "" || endDate == "" {
+		var start, end time.Time
+		switch {
+		case f.last != "":
+			if f.startDate != "" || f.endDate != "" {
+				return nil, errors.New("--last cannot be combined with --start-date/--end-date")
+			}
+			var err error
+			if start, end, err = parseLast(f.last, now, false); err != nil {
+				return nil, err
+			}
+		case f.startDate == "" && f.endDate == "":
+			return oura.BuildQuery(params), nil
+		case f.startDate == "" || f.endDate == "":
 			return nil, errors.New("start-date and end-date must both be set")
-		}
-		start, err := parseDate(startDate)
-		if err != nil {
-			return nil, err
-		}
-		end, err := parseDate(endDate)
-		if err != nil {
-			return nil, err
+		default:
+			var err error
+			if start, _, err = parseDateExpr(f.startDate, now); err != nil {
+				return nil, err
+			}
+			if _, end, err = parseDateExpr(f.endDate, now); err != nil {
+				return nil, err
+			}
 		}
 		if end.Before(start) {
 			return nil, errors.New("end-date must be after start-date")
@@ -132,22 +378,48 @@ func buildListQuery(resource oura.Resource, startDate, endDate, startDateTime, e
 		params["start_date"] = formatDate(start)
 		params["end_date"] = formatDate(end)
 	case oura.QueryDateTime:
-		if startDate != "" || endDate != "" {
-			return nil, errors.New("use --start-datetime/--end-datetime for this resource")
+		days := f.startDate != "" || f.endDate != ""
+		if days && (f.startDateTime != "" || f.endDateTime != "") {
+			return nil, errors.New("use either --start-date/--end-date or --start-datetime/--end-datetime")
 		}
-		if startDateTime == "" && endDateTime == "" {
-			break
-		}
-		if startDateTime == "" || endDateTime == "" {
+		var start, end time.Time
+		switch {
+		case f.last != "":
+			if days || f.startDateTime != "" || f.endDateTime != "" {
+				return nil, errors.New("--last cannot be combined with other date filters")
+			}
+			var err error
+			if start, end, err = parseLast(f.last, now, true); err != nil {
+				return nil, err
+			}
+		case days:
+			if f.startDate == "" || f.endDate == "" {
+				return nil, errors.New("start-date and end-date must both be set")
+			}
+			first, _, err := parseDateExpr(f.startDate, now)
+			if err != nil {
+				return nil, err
+			}
+			_, lastDay, err := parseDateExpr(f.endDate, now)
+			if err != nil {
+				return nil, err
+			}
+			if lastDay.Before(first) {
+				return nil, errors.New("end-date must be after start-date")
+			}
+			start, end = first, endOfDay(lastDay)
+		case f.startDateTime == "" && f.endDateTime == "":
+			return oura.BuildQuery(params), nil
+		case f.startDateTime == "" || f.endDateTime == "":
 			return nil, errors.New("start-datetime and end-datetime must both be set")
-		}
-		start, err := parseDateTime(startDateTime)
-		if err != nil {
-			return nil, err
-		}
-		end, err := parseDateTime(endDateTime)
-		if err != nil {
-			return nil, err
+		default:
+			var err error
+			if start, _, err = parseDateTimeExpr(f.startDateTime, now); err != nil {
+				return nil, err
+			}
+			if _, end, err = parseDateTimeExpr(f.endDateTime, now); err != nil {
+				return nil, err
+			}
 		}
 		if end.Before(start) {
 			return nil, errors.New("end-datetime must be after start-datetime")
@@ -0,0 +1,243 @@
+package app
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/output"
+)
+
+func runProfile(printer *output.Printer, opts GlobalOptions, args []string) int {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		printer.Write(profileUsage())
+		return 0
+	}
+	switch args[0] {
+	case "list":
+		return runProfileList(printer, opts, args[1:])
+	case "add":
+		return runProfileAdd(printer, opts, args[1:])
+	case "remove":
+		return runProfileRemove(printer, opts, args[1:])
+	case "use":
+		return runProfileUse(printer, opts, args[1:])
+	default:
+		printer.Errorf("unknown profile command: %s", args[0])
+		printer.WriteErr("\n")
+		printer.WriteErr(profileUsage())
+		return 2
+	}
+}
+
+func runProfileList(printer *output.Printer, opts GlobalOptions, args []string) int {
+	if len(args) > 0 {
+		printer.Errorf("unexpected argument: %s", args[0])
+		return 2
+	}
+	_, file, err := loadConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	current := file.Resolve("")
+	names := make([]string, 0, len(file.Profiles)+1)
+	for name := range file.Profiles {
+		names = append(names, name)
+	}
+	if _, ok := file.Profiles[current]; !ok {
+		names = append(names, current)
+	}
+	sort.Strings(names)
+
+	if opts.JSON {
+		type entry struct {
+			Name     string `json:"name"`
+			Current  bool   `json:"current"`
+			LoggedIn bool   `json:"logged_in"`
+			ClientID string `json:"client_id,omitempty"`
+		}
+		out := make([]entry, 0, len(names))
+		for _, name := range names {
+			cfg := file.Profiles[name]
+			out = append(out, entry{
+				Name:     name,
+				Current:  name == current,
+				LoggedIn: cfg.LoggedIn(),
+				ClientID: cfg.ClientID,
+			})
+		}
+		b, err := json.Marshal(out)
+		if err != nil {
+			printer.Errorf("json encode failed: %v", err)
+			return 1
+		}
+		if err := printer.PrintJSON(b); err != nil {
+			printer.Errorf("output failed: %v", err)
+			return 1
+		}
+		return 0
+	}
+
+	for _, name := range names {
+		cfg := file.Profiles[name]
+		mark := "  "
+		if name == current {
+			mark = "* "
+		}
+		status := "not logged in"
+		if cfg.LoggedIn() {
+			status = "logged in"
+		}
+		printer.Write(mark + name + "\t" + status + "\n")
+	}
+	return 0
+}
+
+func runProfileAdd(printer *output.Printer, opts GlobalOptions, args []string) int {
+	fs := flag.NewFlagSet("profile add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var clientID string
+	var redirectURI string
+	var scopes string
+	var use bool
+	var help bool
+
+	fs.StringVar(&clientID, "client-id", "", "oauth client id")
+	fs.StringVar(&redirectURI, "redirect-uri", "", "oauth redirect uri")
+	fs.StringVar(&scopes, "scopes", "", "scopes")
+	fs.BoolVar(&use, "use", false, "make the new profile current")
+	fs.BoolVar(&help, "help", false, "show help")
+	fs.BoolVar(&help, "h", false, "show help")
+
+	if err := parseFlags(fs, args); err != nil {
+		printer.Errorf("flag error: %v", err)
+		printer.WriteErr("\n")
+		printer.WriteErr(profileAddUsage())
+		return 2
+	}
+	if help {
+		printer.Write(profileAddUsage())
+		return 0
+	}
+	name, ok := profileArg(printer, fs.Args(), profileAddUsage())
+	if !ok {
+		return 2
+	}
+
+	path, file, lock, err := lockConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	if _, exists := file.Profiles[name]; exists {
+		printer.Errorf("profile already exists: %s", name)
+		return 1
+	}
+	cfg := config.Config{ClientID: clientID, RedirectURI: redirectURI}
+	if scopes != "" {
+		cfg.Scopes = parseScopes(scopes)
+	}
+	file.Profiles[name] = cfg
+	if use {
+		file.CurrentProfile = name
+	}
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	printer.Infof("added profile %s", name)
+	return 0
+}
+
+func runProfileRemove(printer *output.Printer, opts GlobalOptions, args []string) int {
+	name, ok := profileArg(printer, args, profileUsage())
+	if !ok {
+		return 2
+	}
+	path, file, lock, err := lockConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	cfg, exists := file.Profiles[name]
+	if !exists {
+		printer.Errorf("unknown profile: %s", name)
+		return 1
+	}
+	if hasSecretRefs(cfg) {
+		store, err := openSecretStore(path, cfg, opts, printer)
+		if err == nil && store != nil {
+			err = deleteSecrets(store, cfg)
+		}
+		if err != nil {
+			printer.Errorf("remove profile secrets failed: %v", err)
+			return 1
+		}
+	}
+	delete(file.Profiles, name)
+	if file.CurrentProfile == name {
+		file.CurrentProfile = ""
+	}
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	if name != config.DefaultProfile {
+		dir := filepath.Dir(profileDataPath(path, name, "cache"))
+		if err := os.RemoveAll(dir); err != nil {
+			printer.Errorf("remove profile data failed: %v", err)
+			return 1
+		}
+	}
+	printer.Infof("removed profile %s", name)
+	return 0
+}
+
+func runProfileUse(printer *output.Printer, opts GlobalOptions, args []string) int {
+	name, ok := profileArg(printer, args, profileUsage())
+	if !ok {
+		return 2
+	}
+	path, file, lock, err := lockConfigFile(opts, printer, configUnlocker(opts, printer))
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+	defer lock.Release()
+	if _, exists := file.Profiles[name]; !exists && name != config.DefaultProfile {
+		printer.Errorf("unknown profile: %s", name)
+		return 1
+	}
+	file.CurrentProfile = name
+	if name == config.DefaultProfile {
+		file.CurrentProfile = ""
+	}
+	if err := config.SaveFile(path, file); err != nil {
+		printer.Errorf("config save failed: %v", err)
+		return 1
+	}
+	printer.Infof("using profile %s", name)
+	return 0
+}
+
+func profileArg(printer *output.Printer, args []string, usage string) (string, bool) {
+	if len(args) != 1 {
+		printer.Errorf("profile name required")
+		printer.WriteErr("\n")
+		printer.WriteErr(usage)
+		return "", false
+	}
+	if !config.ValidProfileName(args[0]) {
+		printer.Errorf("invalid profile name: %q (use letters, digits, '-', '_' or '.')", args[0])
+		return "", false
+	}
+	return args[0], true
+}
@@ -5,8 +5,8 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/mattjefferson/oura-cli/internal/oura"
 	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
 )
 
 func runResources(printer *output.Printer) int {
@@ -0,0 +1,163 @@
+package app
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/internal/secrets"
+)
+
+const secretServiceName = "oura-cli"
+
+func openSecretStore(path string, cfg config.Config, opts GlobalOptions, printer *output.Printer) (secrets.Store, error) {
+	if cfg.SecretStore == nil {
+		return nil, nil
+	}
+	sc := cfg.SecretStore
+	switch sc.Backend {
+	case "", "file":
+		return nil, nil
+	case "secret-tool", "secret-service":
+		return secrets.NewSecretTool(secretServiceName)
+	case "exec":
+		return secrets.NewExec(sc.Get, sc.Set, sc.Delete)
+	case "encrypted-file":
+		file := sc.Path
+		if file == "" {
+			file = filepath.Join(filepath.Dir(path), "secrets.enc")
+		}
+		return secrets.NewEncryptedFile(file, func() (string, error) {
+			if v := os.Getenv("OURA_SECRETS_PASSPHRASE"); v != "" {
+				return v, nil
+			}
+			pass, err := promptSecret("Secrets passphrase: ", printer, opts.NoInput)
+			if err != nil {
+				return "", fmt.Errorf("passphrase required (set OURA_SECRETS_PASSPHRASE): %w", err)
+			}
+			return pass, nil
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown secret_store backend: %s", sc.Backend)
+	}
+}
+
+type secretField struct {
+	name  string
+	value *string
+	ref   *string
+}
+
+func secretFields(cfg *config.Config) []secretField {
+	fields := []secretField{{"client_secret", &cfg.ClientSecret, &cfg.ClientSecretRef}}
+	if cfg.Token != nil {
+		fields = append(fields,
+			secretField{"access_token", &cfg.Token.AccessToken, &cfg.Token.AccessTokenRef},
+			secretField{"refresh_token", &cfg.Token.RefreshToken, &cfg.Token.RefreshTokenRef},
+		)
+	}
+	return fields
+}
+
+func hasPlaintextSecrets(cfg config.Config) bool {
+	for _, f := range secretFields(&cfg) {
+		if *f.value != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func hasSecretRefs(cfg config.Config) bool {
+	for _, f := range secretFields(&cfg) {
+		if *f.ref != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func resolveSecrets(store secrets.Store, cfg *config.Config) error {
+	for _, f := range secretFields(cfg) {
+		if *f.value != "" || *f.ref == "" {
+			continue
+		}
+		v, err := store.Get(*f.ref)
+		if errors.Is(err, secrets.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", f.name, err)
+		}
+		*f.value = v
+	}
+	return nil
+}
+
+func secretKeyPrefix(configPath, profile string) string {
+	path, err := filepath.Abs(configPath)
+	if err != nil {
+		path = configPath
+	}
+	base := filepath.Base(path)
+	sum := sha256.Sum256([]byte(path))
+	return strings.TrimSuffix(base, filepath.Ext(base)) + "-" + hex.EncodeToString(sum[:4]) + "/" + profile
+}
+
+func externalizeSecrets(store secrets.Store, prefix string, cfg, previous config.Config) (config.Config, error) {
+	if cfg.Token != nil {
+		token := *cfg.Token
+		cfg.Token = &token
+	}
+	previousRefs := map[string]string{}
+	for _, f := range secretFields(&previous) {
+		previousRefs[f.name] = *f.ref
+	}
+	kept := map[string]bool{}
+	for _, f := range secretFields(&cfg) {
+		if *f.value == "" {
+			*f.ref = ""
+			continue
+		}
+		key := prefix + "/" + f.name
+		if previousRefs[f.name] != key || !secretUnchanged(store, key, *f.value) {
+			if err := store.Set(key, *f.value); err != nil {
+				return cfg, fmt.Errorf("store %s: %w", f.name, err)
+			}
+		}
+		*f.ref = key
+		*f.value = ""
+		kept[key] = true
+	}
+	for _, f := range secretFields(&previous) {
+		if *f.ref != "" && !kept[*f.ref] {
+			if err := store.Delete(*f.ref); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+				return cfg, fmt.Errorf("delete %s: %w", f.name, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+func secretUnchanged(store secrets.Store, key, value string) bool {
+	stored, err := store.Get(key)
+	return err == nil && stored == value
+}
+
+func deleteSecrets(store secrets.Store, cfg config.Config) error {
+	for _, f := range secretFields(&cfg) {
+		if *f.ref == "" {
+			continue
+		}
+		if err := store.Delete(*f.ref); err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("delete %s: %w", f.name, err)
+		}
+	}
+	return nil
+}
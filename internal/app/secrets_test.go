@@ -0,0 +1,63 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/secrets"
+)
+
+type memoryStore struct {
+	values map[string]string
+	sets   []string
+}
+
+func (m *memoryStore) Get(key string) (string, error) {
+	v, ok := m.values[key]
+	if !ok {
+		return "", secrets.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m *memoryStore) Set(key, value string) error {
+	m.values[key] = value
+	m.sets = append(m.sets, key)
+	return nil
+}
+
+func (m *memoryStore) Delete(key string) error {
+	delete(m.values, key)
+	return nil
+}
+
+func TestExternalizeSecretsSetsOnlyChanges(t *testing.T) {
+	store := &memoryStore{values: map[string]string{}}
+	cfg := config.Config{
+		ClientSecret: "secret",
+		Token:        &config.Token{AccessToken: "access-1", RefreshToken: "refresh-1"},
+	}
+	previous, err := externalizeSecrets(store, "p", cfg, config.Config{})
+	if err != nil {
+		t.Fatalf("first save: %v", err)
+	}
+	if len(store.sets) != 3 {
+		t.Fatalf("first save set %v, want all three secrets", store.sets)
+	}
+
+	store.sets = nil
+	cfg.Token = &config.Token{AccessToken: "access-2", RefreshToken: "refresh-1"}
+	saved, err := externalizeSecrets(store, "p", cfg, previous)
+	if err != nil {
+		t.Fatalf("second save: %v", err)
+	}
+	if len(store.sets) != 1 || store.sets[0] != "p/access_token" {
+		t.Errorf("refresh save set %v, want only p/access_token", store.sets)
+	}
+	if saved.ClientSecret != "" || saved.ClientSecretRef != "p/client_secret" || saved.Token.AccessTokenRef != "p/access_token" {
+		t.Errorf("saved config kept plaintext or lost refs: %+v %+v", saved, saved.Token)
+	}
+	if store.values["p/access_token"] != "access-2" {
+		t.Errorf("stored access token = %q", store.values["p/access_token"])
+	}
+}
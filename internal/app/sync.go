@@ -0,0 +1,229 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/internal/store"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+const heartrateChunkDays = 7
+
+func runSync(printer *output.Printer, opts GlobalOptions, args []string) int {
+	fs := flag.NewFlagSet("sync", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	var resourceList string
+	var since string
+	var window int
+	var storeDir string
+	var sandbox bool
+	var help bool
+
+	fs.StringVar(&resourceList, "resources", "", "resources to sync")
+	fs.StringVar(&since, "since", "", "first day for an initial sync")
+	fs.IntVar(&window, "window", 3, "days to re-fetch before the last synced day")
+	fs.StringVar(&storeDir, "store", "", "store directory")
+	fs.BoolVar(&sandbox, "sandbox", false, "use sandbox")
+	fs.BoolVar(&help, "help", false, "show help")
+	fs.BoolVar(&help, "h", false, "show help")
+
+	if err := parseFlags(fs, args); err != nil {
+		printer.Errorf("flag error: %v", err)
+		printer.WriteErr("\n")
+		printer.WriteErr(syncUsage())
+		return 2
+	}
+	if help {
+		printer.Write(syncUsage())
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		printer.Errorf("unexpected argument: %s", fs.Args()[0])
+		return 2
+	}
+	if window < 0 {
+		printer.Errorf("window must be >= 0")
+		return 2
+	}
+
+	resources, err := syncResources(resourceList)
+	if err != nil {
+		printer.Errorf("%v", err)
+		return 2
+	}
+
+	loaded, err := loadConfig(opts, printer)
+	if err != nil {
+		printer.Errorf("config load failed: %v", err)
+		return 1
+	}
+
+	loc, err := userLocation(opts, loaded.Cfg.Timezone)
+	if err != nil {
+		printer.Errorf("%v", err)
+		return 2
+	}
+	now := time.Now().In(loc)
+	today := startOfDay(now)
+	first := today.AddDate(0, 0, -30)
+	if since != "" {
+		t, _, err := parseDateExpr(since, now)
+		if err != nil {
+			printer.Errorf("invalid --since: %v", err)
+			return 2
+		}
+		first = t
+	}
+
+	opts.NoCache = true
+	client, code, err := newClient(&loaded, opts, printer)
+	if err != nil {
+		printer.Errorf("auth required: %v", err)
+		return code
+	}
+
+	if storeDir == "" {
+		storeDir = loaded.dataPath("store")
+	}
+	st, err := store.Open(storeDir)
+	if err != nil {
+		printer.Errorf("store open failed: %v", err)
+		return 1
+	}
+	defer st.Close()
+
+	state, err := st.State()
+	if err != nil {
+		printer.Errorf("store state read failed: %v", err)
+		return 1
+	}
+
+	exit := 0
+	for _, resource := range resources {
+		if !sandbox {
+			if err := checkScopes(loaded, resource); err != nil {
+				if resourceList != "" {
+					printer.Errorf("%v", err)
+					exit = max(exit, 3)
+				} else {
+					printer.Infof("%s: skipped (missing scope %s)", resource.Key, strings.Join(resource.MissingScopes(loaded.Cfg.Token.GrantedScopes()), ","))
+				}
+				continue
+			}
+		}
+		prev := state.Resources[resource.Key]
+		start := first
+		if prev.LastDay != "" {
+			if last, err := time.ParseInLocation("2006-01-02", prev.LastDay, loc); err == nil {
+				start = last.AddDate(0, 0, -window)
+			}
+		}
+		if start.After(today) {
+			start = today
+		}
+
+		records, err := syncFetch(client, resource, sandbox, start, today)
+		if err != nil {
+			exit = max(exit, reportRequestError(printer, err))
+			printer.Errorf("%s: sync failed", resource.Key)
+			continue
+		}
+		if resource.Query == oura.QueryNextTokenOnly {
+			err = st.WriteAll(resource.Key, records)
+		} else {
+			err = st.WriteDays(resource.Key, start, today, records)
+		}
+		if err != nil {
+			printer.Errorf("%s: store write failed: %v", resource.Key, err)
+			exit = max(exit, 1)
+			continue
+		}
+
+		next := store.ResourceState{SyncedAt: time.Now().UTC(), Records: len(records)}
+		if resource.Query != oura.QueryNextTokenOnly {
+			next.LastDay = formatDate(today)
+		}
+		state.Resources[resource.Key] = next
+		if err := st.SaveState(state); err != nil {
+			printer.Errorf("store state write failed: %v", err)
+			return 1
+		}
+		if resource.Query == oura.QueryNextTokenOnly {
+			printer.Infof("%s: %d records", resource.Key, len(records))
+		} else {
+			printer.Infof("%s: %d records (%s..%s)", resource.Key, len(records), formatDate(start), formatDate(today))
+		}
+	}
+	return exit
+}
+
+func syncResources(list string) ([]oura.Resource, error) {
+	if strings.TrimSpace(list) == "" {
+		var out []oura.Resource
+		for _, r := range oura.Resources() {
+			if r.SupportsList {
+				out = append(out, r)
+			}
+		}
+		return out, nil
+	}
+	var out []oura.Resource
+	for _, name := range parseScopes(list) {
+		r, ok := oura.LookupResource(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown resource: %s", name)
+		}
+		if !r.SupportsList {
+			return nil, fmt.Errorf("resource is not listable: %s", r.Key)
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+
+func syncFetch(client *oura.Client, resource oura.Resource, sandbox bool, start, end time.Time) ([]json.RawMessage, error) {
+	ctx := context.Background()
+	path := oura.BuildPath(sandbox, resource.PathSegment)
+	switch resource.Query {
+	case oura.QueryNextTokenOnly:
+		return oura.ListAll[json.RawMessage](ctx, client, path, nil)
+	case oura.QueryDateTime:
+		var out []json.RawMessage
+		seen := map[string]bool{}
+		stop := endOfDay(end)
+		for from := start; from.Before(stop); from = from.AddDate(0, 0, heartrateChunkDays) {
+			to := endOfDay(from.AddDate(0, 0, heartrateChunkDays-1))
+			if to.After(stop) {
+				to = stop
+			}
+			query := url.Values{}
+			query.Set("start_datetime", formatDateTime(from))
+			query.Set("end_datetime", formatDateTime(to))
+			recs, err := oura.ListAll[json.RawMessage](ctx, client, path, query)
+			if err != nil {
+				return nil, err
+			}
+			for _, rec := range recs {
+				if !seen[string(rec)] {
+					seen[string(rec)] = true
+					out = append(out, rec)
+				}
+			}
+		}
+		return out, nil
+	default:
+		query := url.Values{}
+		query.Set("start_date", formatDate(start))
+		query.Set("end_date", formatDate(end))
+		return oura.ListAll[json.RawMessage](ctx, client, path, query)
+	}
+}
@@ -0,0 +1,160 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+const defaultRefreshSkew = 5 * time.Minute
+
+var errTokenNotSaved = errors.New("refreshed token could not be saved")
+
+type configTokenSource struct {
+	cfg        *config.Config
+	loaded     *loadedConfig
+	skew       time.Duration
+	env        config.EnvOverrides
+	endpoint   oura.Endpoint
+	httpClient *http.Client
+	printer    *output.Printer
+}
+
+func newTokenSource(loaded *loadedConfig, opts GlobalOptions, httpClient *http.Client, printer *output.Printer) *configTokenSource {
+	return &configTokenSource{
+		cfg:        &loaded.Cfg,
+		loaded:     loaded,
+		skew:       loaded.refreshSkew(opts),
+		env:        loaded.Env,
+		endpoint:   loaded.endpoint(opts),
+		httpClient: httpClient,
+		printer:    printer,
+	}
+}
+
+func (s *configTokenSource) Token(ctx context.Context) (string, error) {
+	if s.cfg.Token == nil || s.cfg.Token.AccessToken == "" {
+		return "", errors.New("missing access token")
+	}
+	if !s.env.AccessToken && s.expiresSoon(s.cfg.Token) && s.cfg.Token.RefreshToken != "" {
+		s.printer.Debugf("access token expires at %s; refreshing", s.cfg.Token.ExpiresAt)
+		if _, err := s.refresh(ctx, false); err != nil {
+			if errors.Is(err, errTokenNotSaved) {
+				return "", err
+			}
+			s.printer.Debugf("proactive token refresh failed: %v", err)
+		}
+	}
+	return s.cfg.Token.AccessToken, nil
+}
+
+func (s *configTokenSource) Refresh(ctx context.Context) (string, error) {
+	token, err := s.refresh(ctx, true)
+	if errors.Is(err, errTokenNotSaved) {
+		s.printer.Errorf("%v", err)
+	}
+	return token, err
+}
+
+func (s *configTokenSource) refresh(ctx context.Context, rejected bool) (string, error) {
+	if s.cfg.Token == nil || s.cfg.Token.RefreshToken == "" {
+		return "", errors.New("no refresh token")
+	}
+	if s.cfg.ClientID == "" || (s.cfg.ClientSecret == "" && !s.cfg.PublicClient) {
+		return "", errors.New("missing client credentials for token refresh")
+	}
+	if s.env.AccessToken && !s.env.RefreshToken {
+		return "", errors.New("OURA_ACCESS_TOKEN is set; not refreshing with the stored refresh token")
+	}
+
+	if s.persistAllowed() {
+		lock, err := lockConfig(s.loaded.Path)
+		if err != nil {
+			return "", err
+		}
+		defer lock.Release()
+		if s.adoptStored(rejected) {
+			s.printer.Debugf("using token refreshed by another process")
+			return s.cfg.Token.AccessToken, nil
+		}
+	}
+
+	tokenResp, err := oura.RefreshToken(ctx, s.httpClient, s.endpoint, s.cfg.ClientID, s.cfg.ClientSecret, s.cfg.Token.RefreshToken)
+	if err != nil {
+		return "", err
+	}
+	applyTokenResponse(s.cfg.Token, tokenResp)
+
+	if s.persistAllowed() {
+		if err := s.loaded.writeConfig(*s.cfg); err != nil {
+			return "", fmt.Errorf("%w: %v", errTokenNotSaved, err)
+		}
+	}
+	return s.cfg.Token.AccessToken, nil
+}
+
+func (s *configTokenSource) adoptStored(rejected bool) bool {
+	stored, err := s.loaded.reload()
+	if err != nil {
+		s.printer.Debugf("config reload failed: %v", err)
+		return false
+	}
+	latest := stored.Token
+	if latest == nil || latest.AccessToken == "" {
+		return false
+	}
+	current := s.cfg.Token
+	if latest.RefreshToken != "" {
+		current.RefreshToken = latest.RefreshToken
+	}
+	if latest.AccessToken == current.AccessToken || (!rejected && s.expiresSoon(latest)) {
+		return false
+	}
+	*current = *latest
+	return true
+}
+
+func (s *configTokenSource) expiresSoon(token *config.Token) bool {
+	return expiresWithin(token, s.skew)
+}
+
+func expiresWithin(token *config.Token, d time.Duration) bool {
+	if token == nil || token.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, token.ExpiresAt)
+	if err != nil {
+		return false
+	}
+	return time.Until(expiresAt) <= d
+}
+
+func (s *configTokenSource) persistAllowed() bool {
+	if s.env.AccessToken || s.env.RefreshToken {
+		return false
+	}
+	return true
+}
+
+func applyTokenResponse(token *config.Token, resp oura.TokenResponse) {
+	token.AccessToken = resp.AccessToken
+	if resp.RefreshToken != "" {
+		token.RefreshToken = resp.RefreshToken
+	}
+	if resp.ExpiresIn > 0 {
+		expiresAt := time.Now().UTC().Add(time.Duration(resp.ExpiresIn) * time.Second)
+		token.ExpiresAt = expiresAt.Format(time.RFC3339)
+	}
+	if resp.TokenType != "" {
+		token.TokenType = resp.TokenType
+	}
+	if resp.Scope != "" {
+		token.Scope = resp.Scope
+	}
+}
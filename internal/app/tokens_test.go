@@ -0,0 +1,60 @@
+package app
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/mattjefferson/oura-cli/internal/config"
+	"github.com/mattjefferson/oura-cli/internal/output"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+func TestEnvTokenIgnoresStoredExpiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected token request: %s %s", r.Method, r.URL)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), config.DefaultFileName)
+	stored := config.Config{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Token: &config.Token{
+			AccessToken:  "stored-access",
+			RefreshToken: "stored-refresh",
+			ExpiresAt:    "2020-01-01T00:00:00Z",
+		},
+	}
+	if err := config.Save(path, config.DefaultProfile, stored, nil); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	t.Setenv("OURA_ACCESS_TOKEN", "env-access")
+	cfg := stored
+	token := *stored.Token
+	cfg.Token = &token
+	loaded := loadedConfig{Path: path, Profile: config.DefaultProfile, Env: config.ApplyEnv(&cfg), Cfg: cfg}
+
+	printer := output.New(io.Discard, io.Discard, true, false, false, false)
+	s := newTokenSource(&loaded, GlobalOptions{RefreshSkew: -1}, server.Client(), printer)
+	s.endpoint = oura.Endpoint{TokenURL: server.URL + "/oauth/token"}
+
+	got, err := s.Token(context.Background())
+	if err != nil || got != "env-access" {
+		t.Fatalf("Token() = %q, %v; want env-access", got, err)
+	}
+	if _, err := s.Refresh(context.Background()); err == nil {
+		t.Fatal("Refresh() used the stored refresh token for an env access token")
+	}
+	after, _, err := config.Load(path, config.DefaultProfile, nil)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if after.Token == nil || after.Token.RefreshToken != "stored-refresh" {
+		t.Errorf("stored token changed: %+v", after.Token)
+	}
+}
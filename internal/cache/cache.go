@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+type entry struct {
+	Key       string    `json:"key"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Status    int       `json:"status"`
+	Body      []byte    `json:"body"`
+}
+
+type Dir struct {
+	path     string
+	override time.Duration
+	logf     func(format string, args ...any)
+}
+
+func New(path string, override time.Duration, logf func(format string, args ...any)) *Dir {
+	if logf == nil {
+		logf = func(string, ...any) {}
+	}
+	return &Dir{path: path, override: override, logf: logf}
+}
+
+func (d *Dir) TTL(path string, query url.Values, now time.Time) time.Duration {
+	if d.override > 0 {
+		return d.override
+	}
+	return oura.DefaultCacheTTL(path, query, now)
+}
+
+func (d *Dir) Get(key string) (oura.Response, bool) {
+	file := d.file(key)
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			d.logf("cache read failed: %v", err)
+		}
+		return oura.Response{}, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return oura.Response{}, false
+	}
+	if time.Now().After(e.ExpiresAt) {
+		_ = os.Remove(file)
+		return oura.Response{}, false
+	}
+	return oura.Response{Status: e.Status, Body: e.Body}, true
+}
+
+func (d *Dir) Set(key string, resp oura.Response, ttl time.Duration) {
+	data, err := json.Marshal(entry{Key: key, ExpiresAt: time.Now().Add(ttl), Status: resp.Status, Body: resp.Body})
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(d.path, 0700); err != nil {
+		d.logf("cache write failed: %v", err)
+		return
+	}
+	file := d.file(key)
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		d.logf("cache write failed: %v", err)
+		return
+	}
+	if err := os.Rename(tmp, file); err != nil {
+		d.logf("cache write failed: %v", err)
+	}
+}
+
+func (d *Dir) Clear() error {
+	return os.RemoveAll(d.path)
+}
+
+func (d *Dir) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.path, hex.EncodeToString(sum[:])+".json")
+}
@@ -6,21 +6,114 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/mattjefferson/oura-cli/internal/crypt"
 )
 
 type Token struct {
-	AccessToken  string `json:"access_token,omitempty"`
-	RefreshToken string `json:"refresh_token,omitempty"`
-	ExpiresAt    string `json:"expires_at,omitempty"`
-	TokenType    string `json:"token_type,omitempty"`
+	AccessToken     string `json:"access_token,omitempty"`
+	RefreshToken    string `json:"refresh_token,omitempty"`
+	AccessTokenRef  string `json:"access_token_ref,omitempty"`
+	RefreshTokenRef string `json:"refresh_token_ref,omitempty"`
+	ExpiresAt       string `json:"expires_at,omitempty"`
+	TokenType       string `json:"token_type,omitempty"`
+	Scope           string `json:"scope,omitempty"`
+}
+
+func (t *Token) GrantedScopes() []string {
+	if t == nil {
+		return nil
+	}
+	return strings.Fields(t.Scope)
+}
+
+type SecretStore struct {
+	Backend string  `json:"backend"`
+	Path    string  `json:"path,omitempty"`
+	Get     Command `json:"get,omitempty"`
+	Set     Command `json:"set,omitempty"`
+	Delete  Command `json:"delete,omitempty"`
+}
+
+type Command []string
+
+func (c *Command) UnmarshalJSON(data []byte) error {
+	var line string
+	if err := json.Unmarshal(data, &line); err == nil {
+		*c = strings.Fields(line)
+		return nil
+	}
+	var argv []string
+	if err := json.Unmarshal(data, &argv); err != nil {
+		return errors.New("command must be an array of arguments")
+	}
+	*c = argv
+	return nil
 }
 
 type Config struct {
-	ClientID     string   `json:"client_id,omitempty"`
-	ClientSecret string   `json:"client_secret,omitempty"`
-	RedirectURI  string   `json:"redirect_uri,omitempty"`
-	Scopes       []string `json:"scopes,omitempty"`
-	Token        *Token   `json:"token,omitempty"`
+	ClientID        string       `json:"client_id,omitempty"`
+	ClientSecret    string       `json:"client_secret,omitempty"`
+	ClientSecretRef string       `json:"client_secret_ref,omitempty"`
+	RedirectURI     string       `json:"redirect_uri,omitempty"`
+	DisablePKCE     bool         `json:"disable_pkce,omitempty"`
+	PublicClient    bool         `json:"public_client,omitempty"`
+	Scopes          []string     `json:"scopes,omitempty"`
+	Token           *Token       `json:"token,omitempty"`
+	SecretStore     *SecretStore `json:"secret_store,omitempty"`
+	APIBaseURL      string       `json:"api_base_url,omitempty"`
+	AuthorizeURL    string       `json:"authorize_url,omitempty"`
+	TokenURL        string       `json:"token_url,omitempty"`
+	RevokeURL       string       `json:"revoke_url,omitempty"`
+	RateLimit       int          `json:"rate_limit_per_minute,omitempty"`
+	RefreshSkew     int          `json:"refresh_skew_seconds,omitempty"`
+	Timezone        string       `json:"timezone,omitempty"`
+}
+
+func (c Config) LoggedIn() bool {
+	return c.Token != nil && (c.Token.AccessToken != "" || c.Token.AccessTokenRef != "")
+}
+
+const DefaultProfile = "default"
+
+const DefaultFileName = "config.json"
+
+type File struct {
+	CurrentProfile string            `json:"current_profile,omitempty"`
+	Profiles       map[string]Config `json:"profiles"`
+
+	legacy     bool
+	passphrase string
+}
+
+type Unlocker func() (string, error)
+
+var ErrEncrypted = errors.New("config is encrypted; set OURA_CONFIG_PASSPHRASE or use a terminal")
+
+func (f File) Resolve(profile string) string {
+	if profile != "" {
+		return profile
+	}
+	if f.CurrentProfile != "" {
+		return f.CurrentProfile
+	}
+	return DefaultProfile
+}
+
+func (f File) Legacy() bool {
+	return f.legacy
+}
+
+func (f File) Encrypted() bool {
+	return f.passphrase != ""
+}
+
+func (f *File) Encrypt(passphrase string) {
+	f.passphrase = passphrase
+}
+
+func (f *File) Decrypt() {
+	f.passphrase = ""
 }
 
 type EnvOverrides struct {
@@ -30,6 +123,25 @@ type EnvOverrides struct {
 	ClientSecret bool
 	RedirectURI  bool
 	Scopes       bool
+	APIBaseURL   bool
+	AuthorizeURL bool
+	TokenURL     bool
+	RevokeURL    bool
+}
+
+func (o EnvOverrides) Restore(cfg *Config, stored Config) {
+	if o.APIBaseURL {
+		cfg.APIBaseURL = stored.APIBaseURL
+	}
+	if o.AuthorizeURL {
+		cfg.AuthorizeURL = stored.AuthorizeURL
+	}
+	if o.TokenURL {
+		cfg.TokenURL = stored.TokenURL
+	}
+	if o.RevokeURL {
+		cfg.RevokeURL = stored.RevokeURL
+	}
 }
 
 func DefaultPath() (string, error) {
@@ -37,38 +149,117 @@ func DefaultPath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(base, "oura", "config.json"), nil
+	return filepath.Join(base, "oura", DefaultFileName), nil
+}
+
+func Load(path, profile string, unlock Unlocker) (Config, bool, error) {
+	f, ok, err := LoadFile(path, unlock)
+	if err != nil {
+		return Config{}, ok, err
+	}
+	cfg, exists := f.Profiles[f.Resolve(profile)]
+	return cfg, ok && exists, nil
+}
+
+func Save(path, profile string, cfg Config, unlock Unlocker) error {
+	f, _, err := LoadFile(path, unlock)
+	if err != nil {
+		return err
+	}
+	f.Profiles[f.Resolve(profile)] = cfg
+	return SaveFile(path, f)
 }
 
-func Load(path string) (Config, bool, error) {
-	var cfg Config
-	f, err := os.Open(path)
+func LoadFile(path string, unlock Unlocker) (File, bool, error) {
+	f := File{Profiles: map[string]Config{}}
+	data, err := os.ReadFile(path)
 	if err != nil {
 		if errors.Is(err, os.ErrNotExist) {
-			return cfg, false, nil
+			return f, false, nil
 		}
-		return cfg, false, err
+		return f, false, err
 	}
-	defer f.Close()
-	dec := json.NewDecoder(f)
-	if err := dec.Decode(&cfg); err != nil {
-		return cfg, true, err
+	if crypt.IsSealed(data) {
+		if unlock == nil {
+			return f, true, ErrEncrypted
+		}
+		pass, err := unlock()
+		if err != nil {
+			return f, true, err
+		}
+		if data, err = crypt.Open(pass, data); err != nil {
+			return f, true, err
+		}
+		f.passphrase = pass
 	}
-	return cfg, true, nil
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return f, true, err
+	}
+	if _, ok := probe["profiles"]; !ok {
+		var cfg Config
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return f, true, err
+		}
+		f.Profiles[DefaultProfile] = cfg
+		f.legacy = true
+		return f, true, nil
+	}
+	pass := f.passphrase
+	if err := json.Unmarshal(data, &f); err != nil {
+		return f, true, err
+	}
+	f.passphrase = pass
+	if f.Profiles == nil {
+		f.Profiles = map[string]Config{}
+	}
+	return f, true, nil
 }
 
-func Save(path string, cfg Config) error {
+func SaveFile(path string, f File) error {
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
 		return err
 	}
-	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if f.Profiles == nil {
+		f.Profiles = map[string]Config{}
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
 	if err != nil {
 		return err
 	}
-	defer f.Close()
-	enc := json.NewEncoder(f)
-	enc.SetIndent("", "  ")
-	return enc.Encode(cfg)
+	data = append(data, '\n')
+	if f.passphrase != "" {
+		if data, err = crypt.Seal(f.passphrase, data); err != nil {
+			return err
+		}
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func ValidProfileName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_', r == '.':
+		default:
+			return false
+		}
+	}
+	return name != "." && name != ".."
 }
 
 func ApplyEnv(cfg *Config) EnvOverrides {
@@ -89,6 +280,22 @@ func ApplyEnv(cfg *Config) EnvOverrides {
 		cfg.Scopes = splitScopes(v)
 		over.Scopes = true
 	}
+	if v := os.Getenv("OURA_API_BASE_URL"); v != "" {
+		cfg.APIBaseURL = v
+		over.APIBaseURL = true
+	}
+	if v := os.Getenv("OURA_AUTHORIZE_URL"); v != "" {
+		cfg.AuthorizeURL = v
+		over.AuthorizeURL = true
+	}
+	if v := os.Getenv("OURA_TOKEN_URL"); v != "" {
+		cfg.TokenURL = v
+		over.TokenURL = true
+	}
+	if v := os.Getenv("OURA_REVOKE_URL"); v != "" {
+		cfg.RevokeURL = v
+		over.RevokeURL = true
+	}
 	if v := os.Getenv("OURA_ACCESS_TOKEN"); v != "" {
 		if cfg.Token == nil {
 			cfg.Token = &Token{}
@@ -0,0 +1,113 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	version    = 1
+	kdf        = "pbkdf2-sha256"
+	iterations = 600000
+	saltSize   = 16
+	keySize    = 32
+)
+
+var ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+
+type envelope struct {
+	Encrypted  int    `json:"oura_encrypted"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func Seal(passphrase string, plaintext []byte) ([]byte, error) {
+	if passphrase == "" {
+		return nil, errors.New("empty passphrase")
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newGCM(passphrase, salt, iterations)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	env := envelope{
+		Encrypted:  version,
+		KDF:        kdf,
+		Iterations: iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plaintext, nil),
+	}
+	data, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func Open(passphrase string, data []byte) ([]byte, error) {
+	var env envelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, err
+	}
+	if env.Encrypted != version {
+		return nil, fmt.Errorf("unsupported encryption version %d", env.Encrypted)
+	}
+	if env.KDF != kdf {
+		return nil, fmt.Errorf("unsupported kdf %q", env.KDF)
+	}
+	gcm, err := newGCM(passphrase, env.Salt, env.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	if len(env.Nonce) != gcm.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+	plaintext, err := gcm.Open(nil, env.Nonce, env.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+func IsSealed(data []byte) bool {
+	if !bytes.Contains(data, []byte(`"oura_encrypted"`)) {
+		return false
+	}
+	var probe struct {
+		Encrypted int `json:"oura_encrypted"`
+	}
+	return json.Unmarshal(data, &probe) == nil && probe.Encrypted != 0
+}
+
+func newGCM(passphrase string, salt []byte, iter int) (cipher.AEAD, error) {
+	if iter < 1 || len(salt) == 0 {
+		return nil, errors.New("invalid kdf parameters")
+	}
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iter, keySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,282 @@
+package devserver
+
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+func rng(parts ...string) *rand.Rand {
+	h := fnv.New64a()
+	for _, p := range parts {
+		_, _ = h.Write([]byte(p))
+		_, _ = h.Write([]byte{0})
+	}
+	seed := h.Sum64()
+	return rand.New(rand.NewPCG(seed, seed>>1|1))
+}
+
+func between(r *rand.Rand, lo, hi int) int {
+	return lo + r.IntN(hi-lo+1)
+}
+
+func intp(v int) *int {
+	return &v
+}
+
+func floatp(v float64) *float64 {
+	return &v
+}
+
+func strp(v string) *string {
+	return &v
+}
+
+func pick(r *rand.Rand, values ...string) string {
+	return values[r.IntN(len(values))]
+}
+
+func personalInfo() oura.PersonalInfo {
+	return oura.PersonalInfo{
+		ID:            "fake-user",
+		Age:           intp(35),
+		Weight:        floatp(70.5),
+		Height:        floatp(1.75),
+		BiologicalSex: strp("female"),
+		Email:         strp("fake@example.com"),
+	}
+}
+
+func ringConfigurations() []oura.RingConfiguration {
+	return []oura.RingConfiguration{{
+		ID:              "ring_configuration-1",
+		Color:           strp("silver"),
+		Design:          strp("heritage"),
+		FirmwareVersion: strp("2.9.20"),
+		HardwareType:    strp("gen3"),
+		SetUpAt:         strp("2023-01-01T12:00:00+00:00"),
+		Size:            intp(9),
+	}}
+}
+
+func heartRate(start, end time.Time) []oura.HeartRate {
+	var out []oura.HeartRate
+	step := 5 * time.Minute
+	t := start.UTC().Truncate(step)
+	if t.Before(start) {
+		t = t.Add(step)
+	}
+	for ; t.Before(end); t = t.Add(step) {
+		ts := t.Format(time.RFC3339)
+		r := rng("heartrate", ts)
+		source := "awake"
+		if h := t.Hour(); h < 6 || h >= 23 {
+			source = "rest"
+		}
+		bpm := between(r, 60, 110)
+		if source == "rest" {
+			bpm = between(r, 45, 65)
+		}
+		out = append(out, oura.HeartRate{BPM: bpm, Source: source, Timestamp: ts})
+	}
+	return out
+}
+
+func generateDay(key string, date time.Time) (any, bool) {
+	day := date.Format("2006-01-02")
+	id := key + "-" + day
+	ts := date.Format("2006-01-02") + "T00:00:00+00:00"
+	r := rng(key, day)
+
+	switch key {
+	case "daily_activity":
+		steps := between(r, 2000, 16000)
+		return oura.DailyActivity{
+			ID:             id,
+			Score:          intp(between(r, 50, 99)),
+			ActiveCalories: steps / 25,
+			Contributors: oura.ActivityContributors{
+				MeetDailyTargets:  intp(between(r, 40, 100)),
+				MoveEveryHour:     intp(between(r, 40, 100)),
+				RecoveryTime:      intp(between(r, 40, 100)),
+				StayActive:        intp(between(r, 40, 100)),
+				TrainingFrequency: intp(between(r, 40, 100)),
+				TrainingVolume:    intp(between(r, 40, 100)),
+			},
+			EquivalentWalkingDistance: steps * 7 / 10,
+			Met:                       oura.Sample{Interval: 60, Items: []*float64{}, Timestamp: ts},
+			Steps:                     steps,
+			TargetCalories:            500,
+			TargetMeters:              8000,
+			TotalCalories:             2000 + steps/25,
+			Day:                       day,
+			Timestamp:                 ts,
+		}, true
+	case "daily_cardiovascular_age":
+		return oura.DailyCardiovascularAge{ID: id, Day: day, VascularAge: intp(between(r, 30, 40))}, true
+	case "daily_readiness":
+		return oura.DailyReadiness{
+			ID: id,
+			Contributors: oura.ReadinessContributors{
+				ActivityBalance:     intp(between(r, 50, 100)),
+				BodyTemperature:     intp(between(r, 50, 100)),
+				HRVBalance:          intp(between(r, 50, 100)),
+				PreviousDayActivity: intp(between(r, 50, 100)),
+				PreviousNight:       intp(between(r, 50, 100)),
+				RecoveryIndex:       intp(between(r, 50, 100)),
+				RestingHeartRate:    intp(between(r, 50, 100)),
+				SleepBalance:        intp(between(r, 50, 100)),
+			},
+			Day:                       day,
+			Score:                     intp(between(r, 55, 99)),
+			TemperatureDeviation:      floatp(float64(between(r, -50, 50)) / 100),
+			TemperatureTrendDeviation: floatp(float64(between(r, -30, 30)) / 100),
+			Timestamp:                 ts,
+		}, true
+	case "daily_resilience":
+		return oura.DailyResilience{
+			ID:  id,
+			Day: day,
+			Contributors: oura.ResilienceContributors{
+				SleepRecovery:   float64(between(r, 300, 1000)) / 10,
+				DaytimeRecovery: float64(between(r, 300, 1000)) / 10,
+				Stress:          float64(between(r, 300, 1000)) / 10,
+			},
+			Level: pick(r, "limited", "adequate", "solid", "strong", "exceptional"),
+		}, true
+	case "daily_sleep":
+		return oura.DailySleep{
+			ID: id,
+			Contributors: oura.SleepContributors{
+				DeepSleep:   intp(between(r, 50, 100)),
+				Efficiency:  intp(between(r, 50, 100)),
+				Latency:     intp(between(r, 50, 100)),
+				REMSleep:    intp(between(r, 50, 100)),
+				Restfulness: intp(between(r, 50, 100)),
+				Timing:      intp(between(r, 50, 100)),
+				TotalSleep:  intp(between(r, 50, 100)),
+			},
+			Day:       day,
+			Score:     intp(between(r, 55, 99)),
+			Timestamp: ts,
+		}, true
+	case "daily_spo2":
+		return oura.DailySpO2{
+			ID:                        id,
+			Day:                       day,
+			SpO2Percentage:            &oura.SpO2Percentage{Average: float64(between(r, 940, 995)) / 10},
+			BreathingDisturbanceIndex: intp(between(r, 0, 20)),
+		}, true
+	case "daily_stress":
+		return oura.DailyStress{
+			ID:           id,
+			Day:          day,
+			StressHigh:   intp(between(r, 0, 240) * 60),
+			RecoveryHigh: intp(between(r, 0, 240) * 60),
+			DaySummary:   strp(pick(r, "restored", "normal", "stressful")),
+		}, true
+	case "sleep":
+		bedStart := date.Add(-time.Duration(between(r, 60, 180)) * time.Minute)
+		total := between(r, 300, 540) * 60
+		inBed := total + between(r, 10, 60)*60
+		return oura.Sleep{
+			ID:                 id,
+			AverageBreath:      floatp(float64(between(r, 120, 170)) / 10),
+			AverageHeartRate:   floatp(float64(between(r, 480, 650)) / 10),
+			AverageHRV:         intp(between(r, 20, 90)),
+			AwakeTime:          intp(inBed - total),
+			BedtimeStart:       bedStart.Format(time.RFC3339),
+			BedtimeEnd:         bedStart.Add(time.Duration(inBed) * time.Second).Format(time.RFC3339),
+			Day:                day,
+			DeepSleepDuration:  intp(total / 5),
+			Efficiency:         intp(total * 100 / inBed),
+			Latency:            intp(between(r, 2, 30) * 60),
+			LightSleepDuration: intp(total / 2),
+			LowestHeartRate:    intp(between(r, 42, 58)),
+			Period:             0,
+			REMSleepDuration:   intp(total - total/5 - total/2),
+			RestlessPeriods:    intp(between(r, 50, 300)),
+			TimeInBed:          inBed,
+			TotalSleepDuration: intp(total),
+			Type:               "long_sleep",
+		}, true
+	case "sleep_time":
+		return oura.SleepTime{
+			ID:  id,
+			Day: day,
+			OptimalBedtime: &oura.OptimalBedtime{
+				DayTZ:       0,
+				StartOffset: -between(r, 60, 120) * 60,
+				EndOffset:   -between(r, 0, 50) * 60,
+			},
+			Recommendation: strp(pick(r, "follow_optimal_bedtime", "improve_efficiency", "earlier_bedtime")),
+			Status:         strp("optimal_found"),
+		}, true
+	case "session":
+		if r.IntN(3) != 0 {
+			return nil, false
+		}
+		start := date.Add(time.Duration(between(r, 6*60, 21*60)) * time.Minute)
+		return oura.Session{
+			ID:            id,
+			Day:           day,
+			StartDatetime: start.Format(time.RFC3339),
+			EndDatetime:   start.Add(time.Duration(between(r, 5, 30)) * time.Minute).Format(time.RFC3339),
+			Type:          pick(r, "breathing", "meditation", "nap", "relaxation", "rest"),
+			Mood:          strp(pick(r, "bad", "worse", "same", "good", "great")),
+		}, true
+	case "workout":
+		if r.IntN(2) != 0 {
+			return nil, false
+		}
+		start := date.Add(time.Duration(between(r, 6*60, 20*60)) * time.Minute)
+		return oura.Workout{
+			ID:            id,
+			Activity:      pick(r, "walking", "running", "cycling", "swimming", "strength_training"),
+			Calories:      floatp(float64(between(r, 100, 800))),
+			Day:           day,
+			Distance:      floatp(float64(between(r, 1000, 15000))),
+			StartDatetime: start.Format(time.RFC3339),
+			EndDatetime:   start.Add(time.Duration(between(r, 20, 120)) * time.Minute).Format(time.RFC3339),
+			Intensity:     pick(r, "easy", "moderate", "hard"),
+			Source:        pick(r, "manual", "autodetected", "confirmed"),
+		}, true
+	case "tag":
+		if r.IntN(4) != 0 {
+			return nil, false
+		}
+		return oura.Tag{
+			ID:        id,
+			Day:       day,
+			Text:      strp("fake tag"),
+			Timestamp: date.Add(20 * time.Hour).Format(time.RFC3339),
+			Tags:      []string{pick(r, "alcohol", "caffeine", "late_meal", "sauna")},
+		}, true
+	case "enhanced_tag":
+		if r.IntN(4) != 0 {
+			return nil, false
+		}
+		return oura.EnhancedTag{
+			ID:          id,
+			TagTypeCode: strp(pick(r, "tag_generic_alcohol", "tag_generic_caffeine", "tag_generic_late_meal")),
+			StartTime:   date.Add(19 * time.Hour).Format(time.RFC3339),
+			StartDay:    day,
+		}, true
+	case "rest_mode_period":
+		if r.IntN(30) != 0 {
+			return nil, false
+		}
+		return oura.RestModePeriod{
+			ID:        id,
+			EndDay:    strp(date.AddDate(0, 0, 2).Format("2006-01-02")),
+			Episodes:  []oura.RestModeEpisode{{Tags: []string{"sick"}, Timestamp: ts}},
+			StartDay:  day,
+			StartTime: strp(ts),
+		}, true
+	case "vo2_max":
+		return oura.VO2Max{ID: id, Day: day, Timestamp: ts, VO2Max: floatp(float64(between(r, 350, 450)) / 10)}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,437 @@
+package devserver
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+const maxRangeDays = 3660
+
+type Options struct {
+	PageSize   int
+	Today      time.Time
+	FailStatus int
+	FailEvery  int
+	Logf       func(format string, args ...any)
+}
+
+type Server struct {
+	opts Options
+
+	mu        sync.Mutex
+	requests  int
+	failNext  []int
+	tokenSeq  int
+	authCodes map[string]authCode
+	revoked   map[string]bool
+}
+
+type authCode struct {
+	scope     string
+	challenge string
+	method    string
+}
+
+func New(opts Options) *Server {
+	if opts.PageSize <= 0 {
+		opts.PageSize = 50
+	}
+	if opts.Today.IsZero() {
+		opts.Today = time.Now().UTC()
+	}
+	opts.Today = truncateDay(opts.Today)
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	return &Server{opts: opts, authCodes: map[string]authCode{}, revoked: map[string]bool{}}
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth/authorize", s.handleAuthorize)
+	mux.HandleFunc("/oauth/token", s.handleToken)
+	mux.HandleFunc("/oauth/revoke", s.handleRevoke)
+	mux.HandleFunc("/_fake/fail", s.handleFail)
+	mux.HandleFunc("/v2/usercollection/", s.handleCollection)
+	mux.HandleFunc("/v2/sandbox/usercollection/", s.handleCollection)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.opts.Logf("%s %s", r.Method, r.URL.RequestURI())
+		mux.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) Fail(status, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i := 0; i < count; i++ {
+		s.failNext = append(s.failNext, status)
+	}
+}
+
+func (s *Server) nextFailure() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+	if len(s.failNext) > 0 {
+		status := s.failNext[0]
+		s.failNext = s.failNext[1:]
+		return status
+	}
+	if s.opts.FailStatus != 0 && s.opts.FailEvery > 0 && s.requests%s.opts.FailEvery == 0 {
+		return s.opts.FailStatus
+	}
+	return 0
+}
+
+func (s *Server) handleFail(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status, err := strconv.Atoi(q.Get("status"))
+	if err != nil || status < 400 || status > 599 {
+		writeError(w, http.StatusBadRequest, "status must be a 4xx or 5xx code")
+		return
+	}
+	count := 1
+	if v := q.Get("count"); v != "" {
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 1 {
+			writeError(w, http.StatusBadRequest, "count must be a positive integer")
+			return
+		}
+	}
+	s.Fail(status, count)
+	writeJSON(w, http.StatusOK, map[string]any{"status": status, "count": count})
+}
+
+func (s *Server) handleAuthorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil || redirect.Scheme == "" {
+		writeError(w, http.StatusBadRequest, "redirect_uri required")
+		return
+	}
+	s.mu.Lock()
+	s.tokenSeq++
+	code := fmt.Sprintf("fake-code-%d", s.tokenSeq)
+	s.authCodes[code] = authCode{
+		scope:     q.Get("scope"),
+		challenge: q.Get("code_challenge"),
+		method:    q.Get("code_challenge_method"),
+	}
+	s.mu.Unlock()
+
+	rq := redirect.Query()
+	rq.Set("code", code)
+	if state := q.Get("state"); state != "" {
+		rq.Set("state", state)
+	}
+	redirect.RawQuery = rq.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid form")
+		return
+	}
+	scope := "personal daily heartrate workout tag session spo2Daily"
+	switch r.PostForm.Get("grant_type") {
+	case "authorization_code":
+		s.mu.Lock()
+		granted, ok := s.authCodes[r.PostForm.Get("code")]
+		delete(s.authCodes, r.PostForm.Get("code"))
+		s.mu.Unlock()
+		if !ok || !verifyPKCE(granted, r.PostForm.Get("code_verifier")) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+		if granted.scope != "" {
+			scope = granted.scope
+		}
+	case "refresh_token":
+		if token := r.PostForm.Get("refresh_token"); token == "" || s.isRevoked(token) {
+			writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+			return
+		}
+	default:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+	s.mu.Lock()
+	s.tokenSeq++
+	seq := s.tokenSeq
+	s.mu.Unlock()
+	writeJSON(w, http.StatusOK, oura.TokenResponse{
+		AccessToken:  fmt.Sprintf("fake-access-%d", seq),
+		RefreshToken: fmt.Sprintf("fake-refresh-%d", seq),
+		ExpiresIn:    86400,
+		TokenType:    "bearer",
+		Scope:        scope,
+	})
+}
+
+func (s *Server) handleRevoke(w http.ResponseWriter, r *http.Request) {
+	token := r.URL.Query().Get("access_token")
+	if token == "" {
+		writeError(w, http.StatusBadRequest, "access_token required")
+		return
+	}
+	s.mu.Lock()
+	already := s.revoked[token]
+	s.revoked[token] = true
+	s.mu.Unlock()
+	if already {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_token", "error_description": "token already revoked"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{})
+}
+
+func (s *Server) isRevoked(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.revoked[token]
+}
+
+func (s *Server) handleCollection(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		writeError(w, http.StatusUnauthorized, "missing bearer token")
+		return
+	}
+	if s.isRevoked(token) {
+		writeError(w, http.StatusUnauthorized, "token revoked")
+		return
+	}
+	if status := s.nextFailure(); status != 0 {
+		if status == http.StatusTooManyRequests {
+			w.Header().Set("Retry-After", "1")
+		}
+		writeError(w, status, "injected failure")
+		return
+	}
+
+	rest := strings.TrimPrefix(r.URL.Path, "/v2/sandbox/usercollection/")
+	rest = strings.TrimPrefix(rest, "/v2/usercollection/")
+	segment, documentID, _ := strings.Cut(rest, "/")
+	resource, ok := resourceBySegment(segment)
+	if !ok {
+		writeError(w, http.StatusNotFound, "unknown resource")
+		return
+	}
+
+	if resource.Key == "personal_info" {
+		writeJSON(w, http.StatusOK, personalInfo())
+		return
+	}
+	if documentID != "" {
+		s.serveDocument(w, resource, documentID)
+		return
+	}
+	s.serveList(w, r.URL.Query(), resource)
+}
+
+func (s *Server) serveDocument(w http.ResponseWriter, resource oura.Resource, documentID string) {
+	if !resource.SupportsGet {
+		writeError(w, http.StatusNotFound, "not found")
+		return
+	}
+	if resource.Query == oura.QueryNextTokenOnly {
+		for _, doc := range ringConfigurations() {
+			if doc.ID == documentID {
+				writeJSON(w, http.StatusOK, doc)
+				return
+			}
+		}
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	prefix := resource.Key + "-"
+	day, err := time.Parse("2006-01-02", strings.TrimPrefix(documentID, prefix))
+	if !strings.HasPrefix(documentID, prefix) || err != nil || day.After(s.opts.Today) {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	doc, ok := generateDay(resource.Key, day)
+	if !ok {
+		writeError(w, http.StatusNotFound, "document not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, doc)
+}
+
+func (s *Server) serveList(w http.ResponseWriter, q url.Values, resource oura.Resource) {
+	offset, err := decodeNextToken(q.Get("next_token"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid next_token")
+		return
+	}
+
+	var docs []any
+	switch resource.Query {
+	case oura.QueryNextTokenOnly:
+		for _, doc := range ringConfigurations() {
+			docs = append(docs, doc)
+		}
+	case oura.QueryDateTime:
+		start, end, err := s.datetimeRange(q)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, sample := range heartRate(start, end) {
+			docs = append(docs, sample)
+		}
+	default:
+		start, end, err := s.dateRange(q)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if doc, ok := generateDay(resource.Key, day); ok {
+				docs = append(docs, doc)
+			}
+		}
+	}
+
+	if offset > len(docs) {
+		offset = len(docs)
+	}
+	limit := min(offset+s.opts.PageSize, len(docs))
+	page := map[string]any{"data": docs[offset:limit], "next_token": nil}
+	if docs[offset:limit] == nil {
+		page["data"] = []any{}
+	}
+	if limit < len(docs) {
+		page["next_token"] = encodeNextToken(limit)
+	}
+	writeJSON(w, http.StatusOK, page)
+}
+
+func (s *Server) dateRange(q url.Values) (time.Time, time.Time, error) {
+	end := s.opts.Today
+	if v := q.Get("end_date"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_date: %s", v)
+		}
+		end = t
+	}
+	start := end.AddDate(0, 0, -1)
+	if v := q.Get("start_date"); v != "" {
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_date: %s", v)
+		}
+		start = t
+	}
+	if end.After(s.opts.Today) {
+		end = s.opts.Today
+	}
+	if end.Sub(start) > maxRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("date range too large")
+	}
+	return start, end, nil
+}
+
+func (s *Server) datetimeRange(q url.Values) (time.Time, time.Time, error) {
+	end := s.opts.Today.Add(24 * time.Hour)
+	if v := q.Get("end_datetime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid end_datetime: %s", v)
+		}
+		end = t
+	}
+	start := end.Add(-24 * time.Hour)
+	if v := q.Get("start_datetime"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid start_datetime: %s", v)
+		}
+		start = t
+	}
+	if end.Sub(start) > 31*24*time.Hour {
+		return time.Time{}, time.Time{}, fmt.Errorf("datetime range too large")
+	}
+	return start, end, nil
+}
+
+func verifyPKCE(code authCode, verifier string) bool {
+	switch code.method {
+	case "":
+		return code.challenge == "" || code.challenge == verifier
+	case "plain":
+		return code.challenge == verifier
+	case "S256":
+		sum := sha256.Sum256([]byte(verifier))
+		return verifier != "" && base64.RawURLEncoding.EncodeToString(sum[:]) == code.challenge
+	default:
+		return false
+	}
+}
+
+func resourceBySegment(segment string) (oura.Resource, bool) {
+	for _, r := range oura.Resources() {
+		if r.PathSegment == segment {
+			return r, true
+		}
+	}
+	return oura.Resource{}, false
+}
+
+func encodeNextToken(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("offset:" + strconv.Itoa(offset)))
+}
+
+func decodeNextToken(token string) (int, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	v, ok := strings.CutPrefix(string(b), "offset:")
+	if !ok {
+		return 0, fmt.Errorf("invalid token")
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid token")
+	}
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]any{"status": status, "message": message})
+}
+
+func truncateDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
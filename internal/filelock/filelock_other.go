@@ -0,0 +1,13 @@
+//go:build !unix && !windows
+
+package filelock
+
+import "os"
+
+func lock(f *os.File) error {
+	return nil
+}
+
+func unlock(f *os.File) error {
+	return nil
+}
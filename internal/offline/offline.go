@@ -0,0 +1,293 @@
+package offline
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/store"
+	"github.com/mattjefferson/oura-cli/pkg/oura"
+)
+
+const BaseURL = "http://offline.invalid"
+
+type Transport struct {
+	Dir string
+}
+
+func (t Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return respond(req, http.StatusMethodNotAllowed, errorBody("method not allowed")), nil
+	}
+	rest, ok := strings.CutPrefix(req.URL.Path, "/v2/sandbox/usercollection/")
+	if !ok {
+		rest, ok = strings.CutPrefix(req.URL.Path, "/v2/usercollection/")
+	}
+	if !ok {
+		return respond(req, http.StatusNotFound, errorBody("not found")), nil
+	}
+	segment, documentID, _ := strings.Cut(rest, "/")
+	resource, ok := resourceBySegment(segment)
+	if !ok {
+		return respond(req, http.StatusNotFound, errorBody("unknown resource")), nil
+	}
+
+	records, found, err := t.load(resource)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return respond(req, http.StatusNotFound, errorBody("no saved responses for "+resource.Key)), nil
+	}
+
+	if resource.Key == "personal_info" && documentID == "" {
+		if len(records) == 0 {
+			return respond(req, http.StatusNotFound, errorBody("no saved personal_info")), nil
+		}
+		return respondJSON(req, http.StatusOK, records[len(records)-1])
+	}
+	if documentID != "" {
+		id, err := url.PathUnescape(documentID)
+		if err != nil {
+			return respond(req, http.StatusBadRequest, errorBody("invalid document id")), nil
+		}
+		for _, rec := range records {
+			if recordID(rec) == id {
+				return respondJSON(req, http.StatusOK, rec)
+			}
+		}
+		return respond(req, http.StatusNotFound, errorBody("document not found")), nil
+	}
+
+	filtered, err := filter(records, req.URL.Query())
+	if err != nil {
+		return respond(req, http.StatusBadRequest, errorBody(err.Error())), nil
+	}
+	return respondJSON(req, http.StatusOK, oura.ListResponse[json.RawMessage]{Data: filtered})
+}
+
+func (t Transport) load(resource oura.Resource) ([]json.RawMessage, bool, error) {
+	var files []string
+	for _, name := range uniqueNames(resource.Key, resource.PathSegment) {
+		for _, ext := range []string{".json", ".ndjson", ".jsonl"} {
+			path := filepath.Join(t.Dir, name+ext)
+			if info, err := os.Stat(path); err == nil && !info.IsDir() {
+				files = append(files, path)
+			}
+		}
+		root := filepath.Join(t.Dir, name)
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			continue
+		}
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() || strings.HasSuffix(path, ".tmp") {
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".json", ".ndjson", ".jsonl":
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if len(files) == 0 {
+		return nil, false, nil
+	}
+	sort.Strings(files)
+
+	var out []json.RawMessage
+	index := map[string]int{}
+	for _, path := range files {
+		recs, err := readFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, rec := range recs {
+			key := recordID(rec)
+			if key == "" {
+				key = string(rec)
+			}
+			if i, ok := index[key]; ok {
+				out[i] = rec
+				continue
+			}
+			index[key] = len(out)
+			out = append(out, rec)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		return recordTime(out[i]) < recordTime(out[j])
+	})
+	return out, true, nil
+}
+
+func readFile(path string) ([]json.RawMessage, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	var out []json.RawMessage
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return out, nil
+			}
+			return nil, err
+		}
+		recs, err := unwrap(raw)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, recs...)
+	}
+}
+
+func unwrap(raw json.RawMessage) ([]json.RawMessage, error) {
+	trimmed := bytes.TrimSpace(raw)
+	if len(trimmed) == 0 {
+		return nil, nil
+	}
+	if trimmed[0] == '[' {
+		var recs []json.RawMessage
+		err := json.Unmarshal(trimmed, &recs)
+		return recs, err
+	}
+	var envelope struct {
+		Data *[]json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(trimmed, &envelope); err != nil {
+		return nil, err
+	}
+	if envelope.Data != nil {
+		return *envelope.Data, nil
+	}
+	return []json.RawMessage{trimmed}, nil
+}
+
+func filter(records []json.RawMessage, q url.Values) ([]json.RawMessage, error) {
+	startDate, endDate := q.Get("start_date"), q.Get("end_date")
+	var startTime, endTime time.Time
+	var err error
+	if v := q.Get("start_datetime"); v != "" {
+		if startTime, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, err
+		}
+	}
+	if v := q.Get("end_datetime"); v != "" {
+		if endTime, err = time.Parse(time.RFC3339, v); err != nil {
+			return nil, err
+		}
+	}
+
+	out := []json.RawMessage{}
+	for _, rec := range records {
+		if startDate != "" || endDate != "" {
+			day, ok := store.RecordDay(rec)
+			if !ok || (startDate != "" && day < startDate) || (endDate != "" && day > endDate) {
+				continue
+			}
+		}
+		if !startTime.IsZero() || !endTime.IsZero() {
+			ts, err := time.Parse(time.RFC3339, recordTime(rec))
+			if err != nil || (!startTime.IsZero() && ts.Before(startTime)) || (!endTime.IsZero() && ts.After(endTime)) {
+				continue
+			}
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+type recordKeys struct {
+	ID            string `json:"id"`
+	Day           string `json:"day"`
+	StartDay      string `json:"start_day"`
+	Timestamp     string `json:"timestamp"`
+	StartDatetime string `json:"start_datetime"`
+}
+
+func decodeKeys(rec json.RawMessage) recordKeys {
+	var k recordKeys
+	_ = json.Unmarshal(rec, &k)
+	return k
+}
+
+func recordID(rec json.RawMessage) string {
+	return decodeKeys(rec).ID
+}
+
+func recordTime(rec json.RawMessage) string {
+	k := decodeKeys(rec)
+	for _, v := range []string{k.Timestamp, k.StartDatetime, k.Day, k.StartDay} {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func resourceBySegment(segment string) (oura.Resource, bool) {
+	for _, r := range oura.Resources() {
+		if r.PathSegment == segment {
+			return r, true
+		}
+	}
+	return oura.Resource{}, false
+}
+
+func uniqueNames(names ...string) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, n := range names {
+		if !seen[n] {
+			seen[n] = true
+			out = append(out, n)
+		}
+	}
+	return out
+}
+
+func errorBody(message string) []byte {
+	b, _ := json.Marshal(map[string]string{"message": message})
+	return b
+}
+
+func respondJSON(req *http.Request, status int, v any) (*http.Response, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return respond(req, status, b), nil
+}
+
+func respond(req *http.Request, status int, body []byte) *http.Response {
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
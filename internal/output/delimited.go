@@ -0,0 +1,56 @@
+package output
+
+import (
+	"encoding/csv"
+	"encoding/json"
+)
+
+type DelimitedWriter struct {
+	w      *csv.Writer
+	fields []string
+	header bool
+}
+
+func (p *Printer) NewDelimitedWriter(comma rune, fields []string) *DelimitedWriter {
+	w := csv.NewWriter(p.Stdout)
+	w.Comma = comma
+	return &DelimitedWriter{w: w, fields: fields}
+}
+
+func (d *DelimitedWriter) WriteRecords(records []json.RawMessage) error {
+	for _, rec := range records {
+		keys, values, err := Flatten(rec)
+		if err != nil {
+			return err
+		}
+		if !d.header {
+			if len(d.fields) == 0 {
+				d.fields = keys
+			}
+			if err := d.w.Write(d.fields); err != nil {
+				return err
+			}
+			d.header = true
+		}
+		row := make([]string, len(d.fields))
+		for i, f := range d.fields {
+			row[i] = values[f]
+		}
+		if err := d.w.Write(row); err != nil {
+			return err
+		}
+	}
+	d.w.Flush()
+	return d.w.Error()
+}
+
+func (d *DelimitedWriter) Close() error {
+	if !d.header && len(d.fields) > 0 {
+		if err := d.w.Write(d.fields); err != nil {
+			return err
+		}
+		d.header = true
+	}
+	d.w.Flush()
+	return d.w.Error()
+}
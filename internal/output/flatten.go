@@ -0,0 +1,127 @@
+package output
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+)
+
+func Flatten(record json.RawMessage) ([]string, map[string]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(record))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, nil, err
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, nil, errors.New("record is not a json object")
+	}
+	f := flattener{values: map[string]string{}}
+	if err := f.object(dec, record, ""); err != nil {
+		return nil, nil, err
+	}
+	return f.keys, f.values, nil
+}
+
+type flattener struct {
+	keys   []string
+	values map[string]string
+}
+
+func (f *flattener) object(dec *json.Decoder, raw []byte, prefix string) error {
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, ok := tok.(string)
+		if !ok {
+			return errors.New("invalid object key")
+		}
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if err := f.value(dec, raw, key); err != nil {
+			return err
+		}
+	}
+	_, err := dec.Token()
+	return err
+}
+
+func (f *flattener) value(dec *json.Decoder, raw []byte, key string) error {
+	start := dec.InputOffset()
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			return f.object(dec, raw, key)
+		}
+		if err := skipArray(dec); err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, bytes.TrimLeft(raw[start:dec.InputOffset()], " \t\r\n:,")); err != nil {
+			return err
+		}
+		f.set(key, buf.String())
+	case string:
+		f.set(key, v)
+	case json.Number:
+		f.set(key, v.String())
+	case bool:
+		if v {
+			f.set(key, "true")
+		} else {
+			f.set(key, "false")
+		}
+	case nil:
+		f.set(key, "")
+	}
+	return nil
+}
+
+func (f *flattener) set(key, value string) {
+	if _, ok := f.values[key]; !ok {
+		f.keys = append(f.keys, key)
+	}
+	f.values[key] = value
+}
+
+func skipArray(dec *json.Decoder) error {
+	depth := 1
+	for depth > 0 {
+		tok, err := dec.Token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return io.ErrUnexpectedEOF
+			}
+			return err
+		}
+		if d, ok := tok.(json.Delim); ok {
+			switch d {
+			case '[', '{':
+				depth++
+			case ']', '}':
+				depth--
+			}
+		}
+	}
+	return nil
+}
+
+func ParseFields(s string) []string {
+	var out []string
+	for _, f := range strings.Split(s, ",") {
+		f = strings.TrimSpace(f)
+		if f != "" {
+			out = append(out, f)
+		}
+	}
+	return out
+}
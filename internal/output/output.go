@@ -15,6 +15,8 @@ type Printer struct {
 	Verbose     bool
 	JSONCompact bool
 	Pretty      bool
+	Color       bool
+	Width       int
 }
 
 func New(stdout, stderr io.Writer, quiet, verbose, jsonCompact, pretty bool) *Printer {
@@ -70,3 +72,41 @@ func (p *Printer) PrintJSON(body []byte) error {
 	_, err := fmt.Fprintln(p.Stdout, strings.TrimSpace(string(trimmed)))
 	return err
 }
+
+func (p *Printer) PrintNDJSON(records []json.RawMessage) error {
+	var line bytes.Buffer
+	for _, rec := range records {
+		line.Reset()
+		if err := json.Compact(&line, rec); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		if _, err := p.Stdout.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) WithPrefix(prefix string) *Printer {
+	child := *p
+	child.Stderr = &prefixWriter{w: p.Stderr, prefix: []byte(prefix)}
+	return &child
+}
+
+type prefixWriter struct {
+	w      io.Writer
+	prefix []byte
+}
+
+func (pw *prefixWriter) Write(b []byte) (int, error) {
+	var buf bytes.Buffer
+	for line := range bytes.Lines(b) {
+		buf.Write(pw.prefix)
+		buf.Write(line)
+	}
+	if _, err := pw.w.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
@@ -0,0 +1,90 @@
+package output
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiReset = "\x1b[0m"
+	colGap    = 2
+	minColumn = 4
+)
+
+func (p *Printer) PrintTable(headers []string, rows [][]string) error {
+	widths := make([]int, len(headers))
+	for i, h := range headers {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i := range headers {
+			if i < len(row) {
+				widths[i] = max(widths[i], utf8.RuneCountInString(row[i]))
+			}
+		}
+	}
+	fitWidths(widths, p.Width)
+
+	var b strings.Builder
+	writeRow := func(cells []string, header bool) {
+		line := make([]string, len(headers))
+		for i := range headers {
+			cell := ""
+			if i < len(cells) {
+				cell = truncate(cells[i], widths[i])
+			}
+			pad := widths[i] - utf8.RuneCountInString(cell)
+			if i == len(headers)-1 {
+				pad = 0
+			}
+			if header && p.Color {
+				cell = ansiBold + cell + ansiReset
+			}
+			line[i] = cell + strings.Repeat(" ", pad)
+		}
+		b.WriteString(strings.TrimRight(strings.Join(line, strings.Repeat(" ", colGap)), " "))
+		b.WriteByte('\n')
+	}
+	writeRow(headers, true)
+	for _, row := range rows {
+		writeRow(row, false)
+	}
+	_, err := p.Stdout.Write([]byte(b.String()))
+	return err
+}
+
+func fitWidths(widths []int, limit int) {
+	if limit <= 0 || len(widths) == 0 {
+		return
+	}
+	total := func() int {
+		sum := colGap * (len(widths) - 1)
+		for _, w := range widths {
+			sum += w
+		}
+		return sum
+	}
+	for total() > limit {
+		widest := 0
+		for i, w := range widths {
+			if w > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= minColumn {
+			return
+		}
+		widths[widest]--
+	}
+}
+
+func truncate(s string, width int) string {
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	if width <= 1 {
+		return string([]rune(s)[:width])
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
@@ -0,0 +1,112 @@
+package ratelimit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/filelock"
+)
+
+type state struct {
+	Tokens  float64   `json:"tokens"`
+	Updated time.Time `json:"updated"`
+}
+
+type FileBucket struct {
+	path     string
+	capacity float64
+	rate     float64
+	now      func() time.Time
+}
+
+func NewFileBucket(path string, perMinute int) *FileBucket {
+	return &FileBucket{
+		path:     path,
+		capacity: float64(perMinute),
+		rate:     float64(perMinute) / 60,
+		now:      time.Now,
+	}
+}
+
+func (b *FileBucket) Wait(ctx context.Context) error {
+	for {
+		wait, err := b.take()
+		if err != nil {
+			return err
+		}
+		if wait <= 0 {
+			return nil
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+}
+
+func (b *FileBucket) take() (time.Duration, error) {
+	lock, err := filelock.Acquire(b.path + ".lock")
+	if err != nil {
+		return 0, err
+	}
+	defer lock.Release()
+
+	now := b.now()
+	st, err := b.load()
+	if err != nil {
+		return 0, err
+	}
+	if st.Updated.IsZero() || st.Updated.After(now) {
+		st = state{Tokens: b.capacity, Updated: now}
+	}
+	st.Tokens = min(b.capacity, st.Tokens+now.Sub(st.Updated).Seconds()*b.rate)
+	st.Updated = now
+
+	var wait time.Duration
+	if st.Tokens >= 1 {
+		st.Tokens--
+	} else {
+		wait = time.Duration((1 - st.Tokens) / b.rate * float64(time.Second))
+	}
+	if err := b.save(st); err != nil {
+		return 0, err
+	}
+	return wait, nil
+}
+
+func (b *FileBucket) load() (state, error) {
+	var st state
+	data, err := os.ReadFile(b.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return state{}, nil
+	}
+	return st, nil
+}
+
+func (b *FileBucket) save(st state) error {
+	data, err := json.Marshal(st)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(b.path), 0700); err != nil {
+		return err
+	}
+	tmp := b.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, b.path)
+}
@@ -0,0 +1,122 @@
+package secrets
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/mattjefferson/oura-cli/internal/crypt"
+	"github.com/mattjefferson/oura-cli/internal/filelock"
+)
+
+type EncryptedFile struct {
+	path       string
+	passphrase func() (string, error)
+
+	mu     sync.Mutex
+	cached string
+}
+
+func NewEncryptedFile(path string, passphrase func() (string, error)) *EncryptedFile {
+	return &EncryptedFile{path: path, passphrase: passphrase}
+}
+
+func (f *EncryptedFile) Get(key string) (string, error) {
+	values, err := f.read()
+	if err != nil {
+		return "", err
+	}
+	v, ok := values[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (f *EncryptedFile) Set(key, value string) error {
+	return f.update(func(values map[string]string) {
+		values[key] = value
+	})
+}
+
+func (f *EncryptedFile) Delete(key string) error {
+	return f.update(func(values map[string]string) {
+		delete(values, key)
+	})
+}
+
+func (f *EncryptedFile) update(fn func(map[string]string)) error {
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return err
+	}
+	lock, err := filelock.Acquire(f.path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	values, err := f.read()
+	if err != nil {
+		return err
+	}
+	fn(values)
+	plaintext, err := json.Marshal(values)
+	if err != nil {
+		return err
+	}
+	pass, err := f.key()
+	if err != nil {
+		return err
+	}
+	data, err := crypt.Seal(pass, plaintext)
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+func (f *EncryptedFile) read() (map[string]string, error) {
+	values := map[string]string{}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return values, nil
+		}
+		return nil, err
+	}
+	pass, err := f.key()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := crypt.Open(pass, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(plaintext, &values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+func (f *EncryptedFile) key() (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.cached != "" {
+		return f.cached, nil
+	}
+	pass, err := f.passphrase()
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", errors.New("empty passphrase")
+	}
+	f.cached = pass
+	return pass, nil
+}
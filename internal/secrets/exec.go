@@ -0,0 +1,53 @@
+package secrets
+
+import (
+	"errors"
+	"strings"
+)
+
+type Exec struct {
+	GetCommand    []string
+	SetCommand    []string
+	DeleteCommand []string
+}
+
+func NewExec(get, set, del []string) (*Exec, error) {
+	if len(get) == 0 || len(set) == 0 {
+		return nil, errors.New("exec secret store needs get and set commands")
+	}
+	return &Exec{GetCommand: get, SetCommand: set, DeleteCommand: del}, nil
+}
+
+func (e *Exec) Get(key string) (string, error) {
+	out, err := e.run(e.GetCommand, key, "")
+	if err != nil {
+		return "", err
+	}
+	line, _, _ := strings.Cut(out, "\n")
+	line = strings.TrimRight(line, "\r")
+	if line == "" {
+		return "", ErrNotFound
+	}
+	return line, nil
+}
+
+func (e *Exec) Set(key, value string) error {
+	_, err := e.run(e.SetCommand, key, value+"\n")
+	return err
+}
+
+func (e *Exec) Delete(key string) error {
+	if len(e.DeleteCommand) == 0 {
+		return nil
+	}
+	_, err := e.run(e.DeleteCommand, key, "")
+	return err
+}
+
+func (e *Exec) run(argv []string, key, stdin string) (string, error) {
+	args := make([]string, len(argv))
+	for i, arg := range argv {
+		args[i] = strings.ReplaceAll(arg, "{key}", key)
+	}
+	return run(stdin, args[0], args[1:]...)
+}
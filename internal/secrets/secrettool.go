@@ -0,0 +1,41 @@
+package secrets
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+type SecretTool struct {
+	Service string
+}
+
+func NewSecretTool(service string) (*SecretTool, error) {
+	if _, err := exec.LookPath("secret-tool"); err != nil {
+		return nil, errors.New("secret-tool not found on PATH (install libsecret-tools or libsecret)")
+	}
+	return &SecretTool{Service: service}, nil
+}
+
+func (s *SecretTool) Get(key string) (string, error) {
+	out, err := run("", "secret-tool", "lookup", "service", s.Service, "key", key)
+	if err != nil {
+		var cmdErr *CommandError
+		var exitErr *exec.ExitError
+		if errors.As(err, &cmdErr) && cmdErr.Stderr == "" && errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+			return "", ErrNotFound
+		}
+		return "", err
+	}
+	return strings.TrimRight(out, "\r\n"), nil
+}
+
+func (s *SecretTool) Set(key, value string) error {
+	_, err := run(value, "secret-tool", "store", "--label", s.Service+" "+key, "service", s.Service, "key", key)
+	return err
+}
+
+func (s *SecretTool) Delete(key string) error {
+	_, err := run("", "secret-tool", "clear", "service", s.Service, "key", key)
+	return err
+}
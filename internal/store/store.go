@@ -0,0 +1,176 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/mattjefferson/oura-cli/internal/filelock"
+)
+
+const (
+	stateFile = "state.json"
+	allFile   = "_all.json"
+	dayLayout = "2006-01-02"
+)
+
+type Store struct {
+	dir  string
+	lock *filelock.Lock
+}
+
+type ResourceState struct {
+	LastDay  string    `json:"last_day,omitempty"`
+	SyncedAt time.Time `json:"synced_at"`
+	Records  int       `json:"records"`
+}
+
+type State struct {
+	Resources map[string]ResourceState `json:"resources"`
+}
+
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	lock, err := filelock.Acquire(filepath.Join(dir, ".lock"))
+	if err != nil {
+		return nil, err
+	}
+	return &Store{dir: dir, lock: lock}, nil
+}
+
+func (s *Store) Close() error {
+	return s.lock.Release()
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+func (s *Store) State() (State, error) {
+	st := State{Resources: map[string]ResourceState{}}
+	data, err := os.ReadFile(filepath.Join(s.dir, stateFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return st, nil
+		}
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return st, fmt.Errorf("read %s: %w", stateFile, err)
+	}
+	if st.Resources == nil {
+		st.Resources = map[string]ResourceState{}
+	}
+	return st, nil
+}
+
+func (s *Store) SaveState(st State) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(s.dir, stateFile), data)
+}
+
+func (s *Store) WriteDays(resource string, start, end time.Time, records []json.RawMessage) error {
+	byDay := map[string][]json.RawMessage{}
+	for _, rec := range records {
+		day, ok := RecordDayIn(rec, start.Location())
+		if !ok {
+			return fmt.Errorf("%s: record without a day", resource)
+		}
+		byDay[day] = append(byDay[day], rec)
+	}
+	dir := filepath.Join(s.dir, resource)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		day := d.Format(dayLayout)
+		path := filepath.Join(dir, day+".json")
+		recs, ok := byDay[day]
+		delete(byDay, day)
+		if !ok {
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			continue
+		}
+		if err := writeRecords(path, recs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) WriteAll(resource string, records []json.RawMessage) error {
+	dir := filepath.Join(s.dir, resource)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	return writeRecords(filepath.Join(dir, allFile), records)
+}
+
+func RecordDay(rec json.RawMessage) (string, bool) {
+	var fields struct {
+		Day           string `json:"day"`
+		StartDay      string `json:"start_day"`
+		Timestamp     string `json:"timestamp"`
+		StartDatetime string `json:"start_datetime"`
+	}
+	if err := json.Unmarshal(rec, &fields); err != nil {
+		return "", false
+	}
+	for _, v := range []string{fields.Day, fields.StartDay, fields.Timestamp, fields.StartDatetime} {
+		if len(v) >= len(dayLayout) {
+			if _, err := time.Parse(dayLayout, v[:len(dayLayout)]); err == nil {
+				return v[:len(dayLayout)], true
+			}
+		}
+	}
+	return "", false
+}
+
+func RecordDayIn(rec json.RawMessage, loc *time.Location) (string, bool) {
+	var fields struct {
+		Day           string `json:"day"`
+		StartDay      string `json:"start_day"`
+		Timestamp     string `json:"timestamp"`
+		StartDatetime string `json:"start_datetime"`
+	}
+	if err := json.Unmarshal(rec, &fields); err != nil {
+		return "", false
+	}
+	if fields.Day == "" && fields.StartDay == "" {
+		for _, v := range []string{fields.Timestamp, fields.StartDatetime} {
+			if t, err := time.Parse(time.RFC3339, v); err == nil {
+				return t.In(loc).Format(dayLayout), true
+			}
+		}
+	}
+	return RecordDay(rec)
+}
+
+func writeRecords(path string, records []json.RawMessage) error {
+	if records == nil {
+		records = []json.RawMessage{}
+	}
+	data, err := json.Marshal(records)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, append(data, '\n'))
+}
+
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
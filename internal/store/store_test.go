@@ -0,0 +1,80 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWriteDaysAcrossUTCBoundary(t *testing.T) {
+	st, err := Open(t.TempDir())
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer st.Close()
+
+	dir := filepath.Join(st.Dir(), "heartrate")
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(dir, "2026-10-12.json")
+	kept := []byte(`[{"bpm":60,"source":"awake","timestamp":"2026-10-12T12:00:00+00:00"}]` + "\n")
+	if err := os.WriteFile(outside, kept, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skipf("no tzdata: %v", err)
+	}
+	day := time.Date(2026, 10, 13, 0, 0, 0, 0, berlin)
+	records := []json.RawMessage{
+		json.RawMessage(`{"bpm":61,"source":"awake","timestamp":"2026-10-12T22:00:00+00:00"}`),
+		json.RawMessage(`{"bpm":62,"source":"awake","timestamp":"2026-10-13T12:00:00+00:00"}`),
+		json.RawMessage(`{"bpm":63,"source":"awake","timestamp":"2026-10-13T21:55:00+00:00"}`),
+		json.RawMessage(`{"bpm":64,"source":"awake","timestamp":"2026-10-13T22:00:00+00:00"}`),
+	}
+	if err := st.WriteDays("heartrate", day, day, records); err != nil {
+		t.Fatalf("write: %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, "2026-10-13.json"))
+	if err != nil {
+		t.Fatalf("read day: %v", err)
+	}
+	var got []json.RawMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("decode day: %v", err)
+	}
+	if len(got) != 3 {
+		t.Errorf("2026-10-13 holds %d records, want 3: %s", len(got), data)
+	}
+	if data, err := os.ReadFile(outside); err != nil || string(data) != string(kept) {
+		t.Errorf("day before the window was rewritten: %s (%v)", data, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "2026-10-14.json")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("day after the window was written: %v", err)
+	}
+}
+
+func TestRecordDayIn(t *testing.T) {
+	tokyo := time.FixedZone("JST", 9*60*60)
+	tests := []struct {
+		rec  string
+		want string
+	}{
+		{`{"day":"2026-10-01","timestamp":"2026-10-01T20:00:00+00:00"}`, "2026-10-01"},
+		{`{"start_day":"2026-10-01"}`, "2026-10-01"},
+		{`{"timestamp":"2026-10-01T20:00:00+00:00"}`, "2026-10-02"},
+		{`{"start_datetime":"2026-10-01T10:00:00+00:00"}`, "2026-10-01"},
+	}
+	for _, tt := range tests {
+		got, ok := RecordDayIn(json.RawMessage(tt.rec), tokyo)
+		if !ok || got != tt.want {
+			t.Errorf("RecordDayIn(%s) = %q, %v; want %q", tt.rec, got, ok, tt.want)
+		}
+	}
+}
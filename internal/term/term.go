@@ -12,6 +12,14 @@ func IsTTY(f *os.File) bool {
 	return term.IsTerminal(int(f.Fd()))
 }
 
+func Width(f *os.File) int {
+	w, _, err := term.GetSize(int(f.Fd()))
+	if err != nil {
+		return 0
+	}
+	return w
+}
+
 func ReadPassword(prompt string, out io.Writer) (string, error) {
 	if prompt != "" {
 		if _, err := fmt.Fprint(out, prompt); err != nil {
@@ -0,0 +1,70 @@
+package oura
+
+import (
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	ShortCacheTTL  = 5 * time.Minute
+	MediumCacheTTL = time.Hour
+	LongCacheTTL   = 7 * 24 * time.Hour
+)
+
+type Cache interface {
+	Get(key string) (Response, bool)
+	Set(key string, resp Response, ttl time.Duration)
+}
+
+type CacheTTLFunc func(path string, query url.Values, now time.Time) time.Duration
+
+func WithCache(cache Cache, ttl CacheTTLFunc) Option {
+	return func(c *Client) {
+		if ttl == nil {
+			ttl = DefaultCacheTTL
+		}
+		c.cache = cache
+		c.cacheTTL = ttl
+	}
+}
+
+func WithCacheScope(scope string) Option {
+	return func(c *Client) {
+		c.cacheScope = scope
+	}
+}
+
+func DefaultCacheTTL(path string, query url.Values, now time.Time) time.Duration {
+	if strings.HasSuffix(path, "/personal_info") || strings.HasSuffix(path, "/ring_configuration") {
+		return MediumCacheTTL
+	}
+	yesterday := now.AddDate(0, 0, -1).Format("2006-01-02")
+	if end := query.Get("end_date"); end != "" {
+		if end < yesterday {
+			return LongCacheTTL
+		}
+		return ShortCacheTTL
+	}
+	if end := query.Get("end_datetime"); end != "" {
+		if t, err := time.Parse(time.RFC3339, end); err == nil && t.In(now.Location()).Format("2006-01-02") < yesterday {
+			return LongCacheTTL
+		}
+		return ShortCacheTTL
+	}
+	if _, rest, ok := strings.Cut(path, "/usercollection/"); ok && strings.Contains(rest, "/") {
+		return MediumCacheTTL
+	}
+	return ShortCacheTTL
+}
+
+func (c *Client) cacheKey(path string, query url.Values) string {
+	key := c.baseURL + path
+	if len(query) > 0 {
+		key += "?" + query.Encode()
+	}
+	if c.cacheScope != "" {
+		key = c.cacheScope + " " + key
+	}
+	return key
+}
@@ -0,0 +1,261 @@
+package oura
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const DefaultBaseURL = "https://api.ouraring.com"
+
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+type Refresher interface {
+	Refresh(ctx context.Context) (string, error)
+}
+
+type StaticToken string
+
+func (t StaticToken) Token(ctx context.Context) (string, error) {
+	if t == "" {
+		return "", errors.New("missing access token")
+	}
+	return string(t), nil
+}
+
+type Logger interface {
+	Debugf(format string, args ...any)
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debugf(string, ...any) {}
+
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	tokens     TokenSource
+	logger     Logger
+	sandbox    bool
+	retry      RetryPolicy
+	limiter    Limiter
+	cache      Cache
+	cacheTTL   CacheTTLFunc
+	cacheScope string
+}
+
+type Option func(*Client)
+
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		if hc != nil {
+			c.httpClient = hc
+		}
+	}
+}
+
+func WithTokenSource(ts TokenSource) Option {
+	return func(c *Client) {
+		c.tokens = ts
+	}
+}
+
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if baseURL != "" {
+			c.baseURL = strings.TrimRight(baseURL, "/")
+		}
+	}
+}
+
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		if l != nil {
+			c.logger = l
+		}
+	}
+}
+
+func WithSandbox(sandbox bool) Option {
+	return func(c *Client) {
+		c.sandbox = sandbox
+	}
+}
+
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		baseURL:    DefaultBaseURL,
+		logger:     nopLogger{},
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+type APIError struct {
+	Status int
+	Body   []byte
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("api error (%d): %s", e.Status, ErrorMessage(e.Body))
+}
+
+func (c *Client) Get(ctx context.Context, path string, query url.Values) (Response, error) {
+	if c.cache == nil {
+		return c.do(ctx, http.MethodGet, path, query)
+	}
+	key := c.cacheKey(path, query)
+	if resp, ok := c.cache.Get(key); ok {
+		c.logger.Debugf("cache hit %s", key)
+		return resp, nil
+	}
+	c.logger.Debugf("cache miss %s", key)
+	resp, err := c.do(ctx, http.MethodGet, path, query)
+	if err != nil || resp.Status != http.StatusOK {
+		return resp, err
+	}
+	if ttl := c.cacheTTL(path, query, time.Now()); ttl > 0 {
+		c.cache.Set(key, resp, ttl)
+	}
+	return resp, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values) (Response, error) {
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		resp, err := c.send(ctx, method, path, query)
+		if err == nil && resp.Status == http.StatusUnauthorized && !refreshed {
+			refreshed = true
+			if c.refresh(ctx) {
+				attempt--
+				continue
+			}
+		}
+		wait, retry := c.retry.delay(attempt, method, resp, err)
+		if !retry {
+			return resp, err
+		}
+		if err != nil {
+			c.logger.Debugf("attempt %d/%d failed: %v; retrying in %s", attempt+1, c.retry.MaxRetries+1, err, wait.Round(time.Millisecond))
+		} else {
+			c.logger.Debugf("attempt %d/%d got status %d; retrying in %s", attempt+1, c.retry.MaxRetries+1, resp.Status, wait.Round(time.Millisecond))
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return resp, err
+		}
+	}
+}
+
+func (c *Client) send(ctx context.Context, method, path string, query url.Values) (Response, error) {
+	var respData Response
+	if c.tokens == nil {
+		return respData, errors.New("missing token source")
+	}
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return respData, err
+		}
+	}
+	token, err := c.tokens.Token(ctx)
+	if err != nil {
+		return respData, err
+	}
+	u := c.baseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return respData, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	c.logger.Debugf("%s %s", method, u)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return respData, &transportError{err}
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return respData, &transportError{err}
+	}
+
+	respData.Status = resp.StatusCode
+	respData.Header = resp.Header
+	respData.Body = body
+	return respData, nil
+}
+
+func (c *Client) refresh(ctx context.Context) bool {
+	r, ok := c.tokens.(Refresher)
+	if !ok {
+		return false
+	}
+	if _, err := r.Refresh(ctx); err != nil {
+		c.logger.Debugf("token refresh failed: %v", err)
+		return false
+	}
+	return true
+}
+
+func ErrorMessage(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return ""
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(body, &payload); err == nil {
+		if msg, ok := payload["message"].(string); ok && msg != "" {
+			return msg
+		}
+		if msg, ok := payload["error"].(string); ok && msg != "" {
+			return msg
+		}
+	}
+	return text
+}
+
+func BuildPath(sandbox bool, segment string) string {
+	if sandbox {
+		return fmt.Sprintf("/v2/sandbox/usercollection/%s", segment)
+	}
+	return fmt.Sprintf("/v2/usercollection/%s", segment)
+}
+
+func BuildDocumentPath(sandbox bool, segment, documentID string) string {
+	escaped := url.PathEscape(documentID)
+	if sandbox {
+		return fmt.Sprintf("/v2/sandbox/usercollection/%s/%s", segment, escaped)
+	}
+	return fmt.Sprintf("/v2/usercollection/%s/%s", segment, escaped)
+}
+
+func BuildQuery(params map[string]string) url.Values {
+	values := url.Values{}
+	for k, v := range params {
+		if strings.TrimSpace(v) == "" {
+			continue
+		}
+		values.Set(k, v)
+	}
+	return values
+}
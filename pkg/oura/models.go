@@ -0,0 +1,326 @@
+package oura
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type ListResponse[T any] struct {
+	Data      []T     `json:"data"`
+	NextToken *string `json:"next_token"`
+}
+
+func DecodeList[T any](body []byte) (ListResponse[T], error) {
+	var out ListResponse[T]
+	err := json.Unmarshal(body, &out)
+	return out, err
+}
+
+func DecodeDocument[T any](body []byte) (T, error) {
+	var out T
+	err := json.Unmarshal(body, &out)
+	return out, err
+}
+
+var models = map[string]func() any{
+	"personal_info":            func() any { return &PersonalInfo{} },
+	"daily_activity":           func() any { return &DailyActivity{} },
+	"daily_cardiovascular_age": func() any { return &DailyCardiovascularAge{} },
+	"daily_readiness":          func() any { return &DailyReadiness{} },
+	"daily_resilience":         func() any { return &DailyResilience{} },
+	"daily_sleep":              func() any { return &DailySleep{} },
+	"daily_spo2":               func() any { return &DailySpO2{} },
+	"daily_stress":             func() any { return &DailyStress{} },
+	"sleep":                    func() any { return &Sleep{} },
+	"sleep_time":               func() any { return &SleepTime{} },
+	"session":                  func() any { return &Session{} },
+	"workout":                  func() any { return &Workout{} },
+	"tag":                      func() any { return &Tag{} },
+	"enhanced_tag":             func() any { return &EnhancedTag{} },
+	"rest_mode_period":         func() any { return &RestModePeriod{} },
+	"vo2_max":                  func() any { return &VO2Max{} },
+	"ring_configuration":       func() any { return &RingConfiguration{} },
+	"heartrate":                func() any { return &HeartRate{} },
+}
+
+func NewModel(key string) (any, bool) {
+	fn, ok := models[key]
+	if !ok {
+		return nil, false
+	}
+	return fn(), true
+}
+
+func DecodeResource(key string, body []byte) (any, error) {
+	v, ok := NewModel(key)
+	if !ok {
+		return nil, fmt.Errorf("no model for resource: %s", key)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+type Sample struct {
+	Interval  float64    `json:"interval"`
+	Items     []*float64 `json:"items"`
+	Timestamp string     `json:"timestamp"`
+}
+
+type PersonalInfo struct {
+	ID            string   `json:"id"`
+	Age           *int     `json:"age"`
+	Weight        *float64 `json:"weight"`
+	Height        *float64 `json:"height"`
+	BiologicalSex *string  `json:"biological_sex"`
+	Email         *string  `json:"email"`
+}
+
+type ActivityContributors struct {
+	MeetDailyTargets  *int `json:"meet_daily_targets"`
+	MoveEveryHour     *int `json:"move_every_hour"`
+	RecoveryTime      *int `json:"recovery_time"`
+	StayActive        *int `json:"stay_active"`
+	TrainingFrequency *int `json:"training_frequency"`
+	TrainingVolume    *int `json:"training_volume"`
+}
+
+type DailyActivity struct {
+	ID                        string               `json:"id"`
+	Class5Min                 *string              `json:"class_5_min"`
+	Score                     *int                 `json:"score"`
+	ActiveCalories            int                  `json:"active_calories"`
+	AverageMetMinutes         float64              `json:"average_met_minutes"`
+	Contributors              ActivityContributors `json:"contributors"`
+	EquivalentWalkingDistance int                  `json:"equivalent_walking_distance"`
+	HighActivityMetMinutes    int                  `json:"high_activity_met_minutes"`
+	HighActivityTime          int                  `json:"high_activity_time"`
+	InactivityAlerts          int                  `json:"inactivity_alerts"`
+	LowActivityMetMinutes     int                  `json:"low_activity_met_minutes"`
+	LowActivityTime           int                  `json:"low_activity_time"`
+	MediumActivityMetMinutes  int                  `json:"medium_activity_met_minutes"`
+	MediumActivityTime        int                  `json:"medium_activity_time"`
+	Met                       Sample               `json:"met"`
+	MetersToTarget            int                  `json:"meters_to_target"`
+	NonWearTime               int                  `json:"non_wear_time"`
+	RestingTime               int                  `json:"resting_time"`
+	SedentaryMetMinutes       int                  `json:"sedentary_met_minutes"`
+	SedentaryTime             int                  `json:"sedentary_time"`
+	Steps                     int                  `json:"steps"`
+	TargetCalories            int                  `json:"target_calories"`
+	TargetMeters              int                  `json:"target_meters"`
+	TotalCalories             int                  `json:"total_calories"`
+	Day                       string               `json:"day"`
+	Timestamp                 string               `json:"timestamp"`
+}
+
+type DailyCardiovascularAge struct {
+	ID          string `json:"id"`
+	Day         string `json:"day"`
+	VascularAge *int   `json:"vascular_age"`
+}
+
+type ReadinessContributors struct {
+	ActivityBalance     *int `json:"activity_balance"`
+	BodyTemperature     *int `json:"body_temperature"`
+	HRVBalance          *int `json:"hrv_balance"`
+	PreviousDayActivity *int `json:"previous_day_activity"`
+	PreviousNight       *int `json:"previous_night"`
+	RecoveryIndex       *int `json:"recovery_index"`
+	RestingHeartRate    *int `json:"resting_heart_rate"`
+	SleepBalance        *int `json:"sleep_balance"`
+}
+
+type DailyReadiness struct {
+	ID                        string                `json:"id"`
+	Contributors              ReadinessContributors `json:"contributors"`
+	Day                       string                `json:"day"`
+	Score                     *int                  `json:"score"`
+	TemperatureDeviation      *float64              `json:"temperature_deviation"`
+	TemperatureTrendDeviation *float64              `json:"temperature_trend_deviation"`
+	Timestamp                 string                `json:"timestamp"`
+}
+
+type ResilienceContributors struct {
+	SleepRecovery   float64 `json:"sleep_recovery"`
+	DaytimeRecovery float64 `json:"daytime_recovery"`
+	Stress          float64 `json:"stress"`
+}
+
+type DailyResilience struct {
+	ID           string                 `json:"id"`
+	Day          string                 `json:"day"`
+	Contributors ResilienceContributors `json:"contributors"`
+	Level        string                 `json:"level"`
+}
+
+type SleepContributors struct {
+	DeepSleep   *int `json:"deep_sleep"`
+	Efficiency  *int `json:"efficiency"`
+	Latency     *int `json:"latency"`
+	REMSleep    *int `json:"rem_sleep"`
+	Restfulness *int `json:"restfulness"`
+	Timing      *int `json:"timing"`
+	TotalSleep  *int `json:"total_sleep"`
+}
+
+type DailySleep struct {
+	ID           string            `json:"id"`
+	Contributors SleepContributors `json:"contributors"`
+	Day          string            `json:"day"`
+	Score        *int              `json:"score"`
+	Timestamp    string            `json:"timestamp"`
+}
+
+type SpO2Percentage struct {
+	Average float64 `json:"average"`
+}
+
+type DailySpO2 struct {
+	ID                        string          `json:"id"`
+	Day                       string          `json:"day"`
+	SpO2Percentage            *SpO2Percentage `json:"spo2_percentage"`
+	BreathingDisturbanceIndex *int            `json:"breathing_disturbance_index"`
+}
+
+type DailyStress struct {
+	ID           string  `json:"id"`
+	Day          string  `json:"day"`
+	StressHigh   *int    `json:"stress_high"`
+	RecoveryHigh *int    `json:"recovery_high"`
+	DaySummary   *string `json:"day_summary"`
+}
+
+type ReadinessSummary struct {
+	Contributors              ReadinessContributors `json:"contributors"`
+	Score                     *int                  `json:"score"`
+	TemperatureDeviation      *float64              `json:"temperature_deviation"`
+	TemperatureTrendDeviation *float64              `json:"temperature_trend_deviation"`
+}
+
+type Sleep struct {
+	ID                    string            `json:"id"`
+	AverageBreath         *float64          `json:"average_breath"`
+	AverageHeartRate      *float64          `json:"average_heart_rate"`
+	AverageHRV            *int              `json:"average_hrv"`
+	AwakeTime             *int              `json:"awake_time"`
+	BedtimeEnd            string            `json:"bedtime_end"`
+	BedtimeStart          string            `json:"bedtime_start"`
+	Day                   string            `json:"day"`
+	DeepSleepDuration     *int              `json:"deep_sleep_duration"`
+	Efficiency            *int              `json:"efficiency"`
+	HeartRate             *Sample           `json:"heart_rate"`
+	HRV                   *Sample           `json:"hrv"`
+	Latency               *int              `json:"latency"`
+	LightSleepDuration    *int              `json:"light_sleep_duration"`
+	LowBatteryAlert       bool              `json:"low_battery_alert"`
+	LowestHeartRate       *int              `json:"lowest_heart_rate"`
+	Movement30Sec         *string           `json:"movement_30_sec"`
+	Period                int               `json:"period"`
+	Readiness             *ReadinessSummary `json:"readiness"`
+	ReadinessScoreDelta   *int              `json:"readiness_score_delta"`
+	REMSleepDuration      *int              `json:"rem_sleep_duration"`
+	RestlessPeriods       *int              `json:"restless_periods"`
+	SleepPhase5Min        *string           `json:"sleep_phase_5_min"`
+	SleepScoreDelta       *int              `json:"sleep_score_delta"`
+	SleepAlgorithmVersion *string           `json:"sleep_algorithm_version"`
+	TimeInBed             int               `json:"time_in_bed"`
+	TotalSleepDuration    *int              `json:"total_sleep_duration"`
+	Type                  string            `json:"type"`
+}
+
+type OptimalBedtime struct {
+	DayTZ       int `json:"day_tz"`
+	EndOffset   int `json:"end_offset"`
+	StartOffset int `json:"start_offset"`
+}
+
+type SleepTime struct {
+	ID             string          `json:"id"`
+	Day            string          `json:"day"`
+	OptimalBedtime *OptimalBedtime `json:"optimal_bedtime"`
+	Recommendation *string         `json:"recommendation"`
+	Status         *string         `json:"status"`
+}
+
+type Session struct {
+	ID                   string  `json:"id"`
+	Day                  string  `json:"day"`
+	StartDatetime        string  `json:"start_datetime"`
+	EndDatetime          string  `json:"end_datetime"`
+	Type                 string  `json:"type"`
+	HeartRate            *Sample `json:"heart_rate"`
+	HeartRateVariability *Sample `json:"heart_rate_variability"`
+	Mood                 *string `json:"mood"`
+	MotionCount          *Sample `json:"motion_count"`
+}
+
+type Workout struct {
+	ID            string   `json:"id"`
+	Activity      string   `json:"activity"`
+	Calories      *float64 `json:"calories"`
+	Day           string   `json:"day"`
+	Distance      *float64 `json:"distance"`
+	EndDatetime   string   `json:"end_datetime"`
+	Intensity     string   `json:"intensity"`
+	Label         *string  `json:"label"`
+	Source        string   `json:"source"`
+	StartDatetime string   `json:"start_datetime"`
+}
+
+type Tag struct {
+	ID        string   `json:"id"`
+	Day       string   `json:"day"`
+	Text      *string  `json:"text"`
+	Timestamp string   `json:"timestamp"`
+	Tags      []string `json:"tags"`
+}
+
+type EnhancedTag struct {
+	ID          string  `json:"id"`
+	TagTypeCode *string `json:"tag_type_code"`
+	StartTime   string  `json:"start_time"`
+	EndTime     *string `json:"end_time"`
+	StartDay    string  `json:"start_day"`
+	EndDay      *string `json:"end_day"`
+	Comment     *string `json:"comment"`
+	CustomName  *string `json:"custom_name"`
+}
+
+type RestModeEpisode struct {
+	Tags      []string `json:"tags"`
+	Timestamp string   `json:"timestamp"`
+}
+
+type RestModePeriod struct {
+	ID        string            `json:"id"`
+	EndDay    *string           `json:"end_day"`
+	EndTime   *string           `json:"end_time"`
+	Episodes  []RestModeEpisode `json:"episodes"`
+	StartDay  string            `json:"start_day"`
+	StartTime *string           `json:"start_time"`
+}
+
+type VO2Max struct {
+	ID        string   `json:"id"`
+	Day       string   `json:"day"`
+	Timestamp string   `json:"timestamp"`
+	VO2Max    *float64 `json:"vo2_max"`
+}
+
+type RingConfiguration struct {
+	ID              string  `json:"id"`
+	Color           *string `json:"color"`
+	Design          *string `json:"design"`
+	FirmwareVersion *string `json:"firmware_version"`
+	HardwareType    *string `json:"hardware_type"`
+	SetUpAt         *string `json:"set_up_at"`
+	Size            *int    `json:"size"`
+}
+
+type HeartRate struct {
+	BPM       int    `json:"bpm"`
+	Source    string `json:"source"`
+	Timestamp string `json:"timestamp"`
+}
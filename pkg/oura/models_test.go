@@ -0,0 +1,156 @@
+package oura
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+var typedRoundTrips = map[string]func([]byte) ([]byte, error){
+	"personal_info":            documentRoundTrip[PersonalInfo],
+	"daily_activity":           listRoundTrip[DailyActivity],
+	"daily_cardiovascular_age": listRoundTrip[DailyCardiovascularAge],
+	"daily_readiness":          listRoundTrip[DailyReadiness],
+	"daily_resilience":         listRoundTrip[DailyResilience],
+	"daily_sleep":              listRoundTrip[DailySleep],
+	"daily_spo2":               listRoundTrip[DailySpO2],
+	"daily_stress":             listRoundTrip[DailyStress],
+	"sleep":                    listRoundTrip[Sleep],
+	"sleep_time":               listRoundTrip[SleepTime],
+	"session":                  listRoundTrip[Session],
+	"workout":                  listRoundTrip[Workout],
+	"tag":                      listRoundTrip[Tag],
+	"enhanced_tag":             listRoundTrip[EnhancedTag],
+	"rest_mode_period":         listRoundTrip[RestModePeriod],
+	"vo2_max":                  listRoundTrip[VO2Max],
+	"ring_configuration":       listRoundTrip[RingConfiguration],
+	"heartrate":                listRoundTrip[HeartRate],
+}
+
+func listRoundTrip[T any](body []byte) ([]byte, error) {
+	page, err := DecodeList[T](body)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(page)
+}
+
+func documentRoundTrip[T any](body []byte) ([]byte, error) {
+	doc, err := DecodeDocument[T](body)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(doc)
+}
+
+func loadFixture(t *testing.T, key string) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", key+".json"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	return body
+}
+
+func assertSameJSON(t *testing.T, want, got []byte) {
+	t.Helper()
+	var w, g any
+	if err := json.Unmarshal(want, &w); err != nil {
+		t.Fatalf("decode fixture: %v", err)
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("decode re-encoded: %v", err)
+	}
+	if !reflect.DeepEqual(w, g) {
+		t.Errorf("round trip changed the document\nwant: %s\ngot:  %s", want, got)
+	}
+}
+
+func TestModelsCoverResources(t *testing.T) {
+	var resources, modelKeys, typedKeys []string
+	for _, r := range Resources() {
+		resources = append(resources, r.Key)
+	}
+	for key := range models {
+		modelKeys = append(modelKeys, key)
+	}
+	for key := range typedRoundTrips {
+		typedKeys = append(typedKeys, key)
+	}
+	sort.Strings(resources)
+	sort.Strings(modelKeys)
+	sort.Strings(typedKeys)
+	if !reflect.DeepEqual(resources, modelKeys) {
+		t.Errorf("models keys %v do not match resources %v", modelKeys, resources)
+	}
+	if !reflect.DeepEqual(resources, typedKeys) {
+		t.Errorf("typed round trips %v do not match resources %v", typedKeys, resources)
+	}
+}
+
+func TestTypedRoundTrip(t *testing.T) {
+	for _, r := range Resources() {
+		t.Run(r.Key, func(t *testing.T) {
+			body := loadFixture(t, r.Key)
+			got, err := typedRoundTrips[r.Key](body)
+			if err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			assertSameJSON(t, body, got)
+		})
+	}
+}
+
+func TestDecodeResourceRoundTrip(t *testing.T) {
+	for _, r := range Resources() {
+		t.Run(r.Key, func(t *testing.T) {
+			body := loadFixture(t, r.Key)
+			docs := []json.RawMessage{body}
+			if r.SupportsList {
+				page, err := DecodeList[json.RawMessage](body)
+				if err != nil {
+					t.Fatalf("decode list: %v", err)
+				}
+				if len(page.Data) == 0 {
+					t.Fatal("fixture has no records")
+				}
+				docs = page.Data
+			}
+			for _, doc := range docs {
+				v, err := DecodeResource(r.Key, doc)
+				if err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				got, err := json.Marshal(v)
+				if err != nil {
+					t.Fatalf("encode: %v", err)
+				}
+				assertSameJSON(t, doc, got)
+			}
+		})
+	}
+}
+
+func TestDecodeResourceUnknown(t *testing.T) {
+	if _, err := DecodeResource("nope", []byte(`{}`)); err == nil {
+		t.Fatal("expected an error for an unknown resource")
+	}
+}
+
+func TestResourcesReturnCopies(t *testing.T) {
+	r, ok := LookupResource("heartrate")
+	if !ok {
+		t.Fatal("heartrate not found")
+	}
+	r.Scopes[0] = "changed"
+	Resources()[0].Scopes[0] = "changed"
+	if again, _ := LookupResource("heartrate"); again.Scopes[0] != "heartrate" {
+		t.Errorf("LookupResource shares scopes: %v", again.Scopes)
+	}
+	if first := Resources()[0]; first.Scopes[0] == "changed" {
+		t.Errorf("Resources shares scopes: %v", first.Scopes)
+	}
+}
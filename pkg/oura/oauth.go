@@ -0,0 +1,197 @@
+package oura
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type Endpoint struct {
+	AuthorizeURL string
+	TokenURL     string
+	RevokeURL    string
+}
+
+var DefaultEndpoint = Endpoint{
+	AuthorizeURL: "https://cloud.ouraring.com/oauth/authorize",
+	TokenURL:     "https://api.ouraring.com/oauth/token",
+	RevokeURL:    "https://api.ouraring.com/oauth/revoke",
+}
+
+func EndpointForBaseURL(baseURL string) Endpoint {
+	baseURL = strings.TrimRight(baseURL, "/")
+	if baseURL == "" || baseURL == DefaultBaseURL {
+		return DefaultEndpoint
+	}
+	return Endpoint{
+		AuthorizeURL: baseURL + "/oauth/authorize",
+		TokenURL:     baseURL + "/oauth/token",
+		RevokeURL:    baseURL + "/oauth/revoke",
+	}
+}
+
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+}
+
+type PKCE struct {
+	Verifier  string
+	Challenge string
+	Method    string
+}
+
+func NewPKCE() (PKCE, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return PKCE{}, err
+	}
+	verifier := base64.RawURLEncoding.EncodeToString(b)
+	sum := sha256.Sum256([]byte(verifier))
+	return PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+		Method:    "S256",
+	}, nil
+}
+
+type AuthOption func(url.Values)
+
+func WithCodeChallenge(p PKCE) AuthOption {
+	return func(v url.Values) {
+		v.Set("code_challenge", p.Challenge)
+		v.Set("code_challenge_method", p.Method)
+	}
+}
+
+func WithCodeVerifier(p PKCE) AuthOption {
+	return func(v url.Values) {
+		v.Set("code_verifier", p.Verifier)
+	}
+}
+
+func BuildAuthURL(endpoint Endpoint, clientID, redirectURI, state string, scopes []string, opts ...AuthOption) (string, error) {
+	if clientID == "" {
+		return "", errors.New("client_id required")
+	}
+	if redirectURI == "" {
+		return "", errors.New("redirect_uri required")
+	}
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", clientID)
+	q.Set("redirect_uri", redirectURI)
+	if state != "" {
+		q.Set("state", state)
+	}
+	if len(scopes) > 0 {
+		q.Set("scope", strings.Join(scopes, " "))
+	}
+	for _, opt := range opts {
+		opt(q)
+	}
+	return endpoint.AuthorizeURL + "?" + q.Encode(), nil
+}
+
+func ExchangeCode(ctx context.Context, client *http.Client, endpoint Endpoint, clientID, clientSecret, redirectURI, code string, opts ...AuthOption) (TokenResponse, error) {
+	values := url.Values{}
+	values.Set("grant_type", "authorization_code")
+	values.Set("code", code)
+	values.Set("client_id", clientID)
+	if clientSecret != "" {
+		values.Set("client_secret", clientSecret)
+	}
+	values.Set("redirect_uri", redirectURI)
+	for _, opt := range opts {
+		opt(values)
+	}
+	return postToken(ctx, client, endpoint.TokenURL, values)
+}
+
+func RefreshToken(ctx context.Context, client *http.Client, endpoint Endpoint, clientID, clientSecret, refreshToken string) (TokenResponse, error) {
+	values := url.Values{}
+	values.Set("grant_type", "refresh_token")
+	values.Set("refresh_token", refreshToken)
+	values.Set("client_id", clientID)
+	if clientSecret != "" {
+		values.Set("client_secret", clientSecret)
+	}
+	return postToken(ctx, client, endpoint.TokenURL, values)
+}
+
+func RevokeToken(ctx context.Context, client *http.Client, endpoint Endpoint, token string) error {
+	if endpoint.RevokeURL == "" {
+		return errors.New("revoke url not configured")
+	}
+	u, err := url.Parse(endpoint.RevokeURL)
+	if err != nil {
+		return err
+	}
+	q := u.Query()
+	q.Set("access_token", token)
+	u.RawQuery = q.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		return &APIError{Status: resp.StatusCode, Body: body}
+	}
+	return nil
+}
+
+func IsInvalidToken(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || (apiErr.Status != http.StatusBadRequest && apiErr.Status != http.StatusUnauthorized) {
+		return false
+	}
+	var payload struct {
+		Error string `json:"error"`
+	}
+	return json.Unmarshal(apiErr.Body, &payload) == nil && payload.Error == "invalid_token"
+}
+
+func postToken(ctx context.Context, client *http.Client, tokenURL string, values url.Values) (TokenResponse, error) {
+	var token TokenResponse
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(values.Encode()))
+	if err != nil {
+		return token, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return token, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return token, err
+	}
+	if resp.StatusCode >= 400 {
+		return token, fmt.Errorf("token exchange failed: %s", strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, &token); err != nil {
+		return token, err
+	}
+	return token, nil
+}
@@ -1,6 +1,9 @@
 package oura
 
-import "strings"
+import (
+	"slices"
+	"strings"
+)
 
 type QueryKind int
 
@@ -17,27 +20,28 @@ type Resource struct {
 	SupportsList bool
 	SupportsGet  bool
 	Query        QueryKind
+	Scopes       []string
 }
 
 var resources = []Resource{
-	{Key: "personal_info", PathSegment: "personal_info", SupportsGet: true, Query: QueryNone},
-	{Key: "daily_activity", PathSegment: "daily_activity", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_cardiovascular_age", PathSegment: "daily_cardiovascular_age", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_readiness", PathSegment: "daily_readiness", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_resilience", PathSegment: "daily_resilience", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_sleep", PathSegment: "daily_sleep", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_spo2", PathSegment: "daily_spo2", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "daily_stress", PathSegment: "daily_stress", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "sleep", PathSegment: "sleep", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "sleep_time", PathSegment: "sleep_time", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "session", PathSegment: "session", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "workout", PathSegment: "workout", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "tag", PathSegment: "tag", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "enhanced_tag", PathSegment: "enhanced_tag", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "rest_mode_period", PathSegment: "rest_mode_period", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "vo2_max", PathSegment: "vO2_max", SupportsList: true, SupportsGet: true, Query: QueryDate},
-	{Key: "ring_configuration", PathSegment: "ring_configuration", SupportsList: true, SupportsGet: true, Query: QueryNextTokenOnly},
-	{Key: "heartrate", PathSegment: "heartrate", SupportsList: true, SupportsGet: false, Query: QueryDateTime},
+	{Key: "personal_info", PathSegment: "personal_info", SupportsGet: true, Query: QueryNone, Scopes: []string{"personal"}},
+	{Key: "daily_activity", PathSegment: "daily_activity", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "daily_cardiovascular_age", PathSegment: "daily_cardiovascular_age", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "daily_readiness", PathSegment: "daily_readiness", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "daily_resilience", PathSegment: "daily_resilience", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "daily_sleep", PathSegment: "daily_sleep", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "daily_spo2", PathSegment: "daily_spo2", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"spo2Daily"}},
+	{Key: "daily_stress", PathSegment: "daily_stress", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "sleep", PathSegment: "sleep", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "sleep_time", PathSegment: "sleep_time", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "session", PathSegment: "session", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"session"}},
+	{Key: "workout", PathSegment: "workout", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"workout"}},
+	{Key: "tag", PathSegment: "tag", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"tag"}},
+	{Key: "enhanced_tag", PathSegment: "enhanced_tag", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"tag"}},
+	{Key: "rest_mode_period", PathSegment: "rest_mode_period", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "vo2_max", PathSegment: "vO2_max", SupportsList: true, SupportsGet: true, Query: QueryDate, Scopes: []string{"daily"}},
+	{Key: "ring_configuration", PathSegment: "ring_configuration", SupportsList: true, SupportsGet: true, Query: QueryNextTokenOnly, Scopes: []string{"daily"}},
+	{Key: "heartrate", PathSegment: "heartrate", SupportsList: true, SupportsGet: false, Query: QueryDateTime, Scopes: []string{"heartrate"}},
 }
 
 var resourceIndex = func() map[string]Resource {
@@ -51,15 +55,34 @@ var resourceIndex = func() map[string]Resource {
 func LookupResource(name string) (Resource, bool) {
 	key := strings.ToLower(name)
 	r, ok := resourceIndex[key]
-	return r, ok
+	return r.clone(), ok
+}
+
+func (r Resource) MissingScopes(granted []string) []string {
+	have := map[string]bool{}
+	for _, g := range granted {
+		have[g] = true
+	}
+	var missing []string
+	for _, s := range r.Scopes {
+		if !have[s] {
+			missing = append(missing, s)
+		}
+	}
+	return missing
 }
 
 func Resources() []Resource {
 	out := make([]Resource, 0, len(resources))
 	for _, r := range resources {
 		if _, ok := resourceIndex[strings.ToLower(r.Key)]; ok {
-			out = append(out, r)
+			out = append(out, r.clone())
 		}
 	}
 	return out
 }
+
+func (r Resource) clone() Resource {
+	r.Scopes = slices.Clone(r.Scopes)
+	return r
+}
@@ -0,0 +1,159 @@
+package oura
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"time"
+)
+
+func Pages[T any](ctx context.Context, c *Client, path string, query url.Values) iter.Seq2[ListResponse[T], error] {
+	return func(yield func(ListResponse[T], error) bool) {
+		q := cloneValues(query)
+		for {
+			resp, err := c.Get(ctx, path, q)
+			if err != nil {
+				yield(ListResponse[T]{}, err)
+				return
+			}
+			if resp.Status >= 400 {
+				yield(ListResponse[T]{}, &APIError{Status: resp.Status, Body: resp.Body})
+				return
+			}
+			page, err := DecodeList[T](resp.Body)
+			if err != nil {
+				yield(ListResponse[T]{}, err)
+				return
+			}
+			more := page.NextToken != nil && *page.NextToken != ""
+			if !more {
+				page.NextToken = nil
+			}
+			if !yield(page, nil) || !more {
+				return
+			}
+			q.Set("next_token", *page.NextToken)
+		}
+	}
+}
+
+func ListAll[T any](ctx context.Context, c *Client, path string, query url.Values) ([]T, error) {
+	out := []T{}
+	for page, err := range Pages[T](ctx, c, path, query) {
+		if err != nil {
+			return out, err
+		}
+		out = append(out, page.Data...)
+	}
+	return out, nil
+}
+
+func GetDocument[T any](ctx context.Context, c *Client, path string) (T, error) {
+	var zero T
+	resp, err := c.Get(ctx, path, nil)
+	if err != nil {
+		return zero, err
+	}
+	if resp.Status >= 400 {
+		return zero, &APIError{Status: resp.Status, Body: resp.Body}
+	}
+	return DecodeDocument[T](resp.Body)
+}
+
+func listDates[T any](ctx context.Context, c *Client, segment string, start, end time.Time) ([]T, error) {
+	params := map[string]string{}
+	if !start.IsZero() {
+		params["start_date"] = start.Format("2006-01-02")
+	}
+	if !end.IsZero() {
+		params["end_date"] = end.Format("2006-01-02")
+	}
+	return ListAll[T](ctx, c, BuildPath(c.sandbox, segment), BuildQuery(params))
+}
+
+func (c *Client) GetPersonalInfo(ctx context.Context) (PersonalInfo, error) {
+	return GetDocument[PersonalInfo](ctx, c, BuildPath(c.sandbox, "personal_info"))
+}
+
+func (c *Client) ListDailyActivity(ctx context.Context, start, end time.Time) ([]DailyActivity, error) {
+	return listDates[DailyActivity](ctx, c, "daily_activity", start, end)
+}
+
+func (c *Client) ListDailyCardiovascularAge(ctx context.Context, start, end time.Time) ([]DailyCardiovascularAge, error) {
+	return listDates[DailyCardiovascularAge](ctx, c, "daily_cardiovascular_age", start, end)
+}
+
+func (c *Client) ListDailyReadiness(ctx context.Context, start, end time.Time) ([]DailyReadiness, error) {
+	return listDates[DailyReadiness](ctx, c, "daily_readiness", start, end)
+}
+
+func (c *Client) ListDailyResilience(ctx context.Context, start, end time.Time) ([]DailyResilience, error) {
+	return listDates[DailyResilience](ctx, c, "daily_resilience", start, end)
+}
+
+func (c *Client) ListDailySleep(ctx context.Context, start, end time.Time) ([]DailySleep, error) {
+	return listDates[DailySleep](ctx, c, "daily_sleep", start, end)
+}
+
+func (c *Client) ListDailySpO2(ctx context.Context, start, end time.Time) ([]DailySpO2, error) {
+	return listDates[DailySpO2](ctx, c, "daily_spo2", start, end)
+}
+
+func (c *Client) ListDailyStress(ctx context.Context, start, end time.Time) ([]DailyStress, error) {
+	return listDates[DailyStress](ctx, c, "daily_stress", start, end)
+}
+
+func (c *Client) ListSleep(ctx context.Context, start, end time.Time) ([]Sleep, error) {
+	return listDates[Sleep](ctx, c, "sleep", start, end)
+}
+
+func (c *Client) ListSleepTime(ctx context.Context, start, end time.Time) ([]SleepTime, error) {
+	return listDates[SleepTime](ctx, c, "sleep_time", start, end)
+}
+
+func (c *Client) ListSessions(ctx context.Context, start, end time.Time) ([]Session, error) {
+	return listDates[Session](ctx, c, "session", start, end)
+}
+
+func (c *Client) ListWorkouts(ctx context.Context, start, end time.Time) ([]Workout, error) {
+	return listDates[Workout](ctx, c, "workout", start, end)
+}
+
+func (c *Client) ListTags(ctx context.Context, start, end time.Time) ([]Tag, error) {
+	return listDates[Tag](ctx, c, "tag", start, end)
+}
+
+func (c *Client) ListEnhancedTags(ctx context.Context, start, end time.Time) ([]EnhancedTag, error) {
+	return listDates[EnhancedTag](ctx, c, "enhanced_tag", start, end)
+}
+
+func (c *Client) ListRestModePeriods(ctx context.Context, start, end time.Time) ([]RestModePeriod, error) {
+	return listDates[RestModePeriod](ctx, c, "rest_mode_period", start, end)
+}
+
+func (c *Client) ListVO2Max(ctx context.Context, start, end time.Time) ([]VO2Max, error) {
+	return listDates[VO2Max](ctx, c, "vO2_max", start, end)
+}
+
+func (c *Client) ListRingConfigurations(ctx context.Context) ([]RingConfiguration, error) {
+	return ListAll[RingConfiguration](ctx, c, BuildPath(c.sandbox, "ring_configuration"), nil)
+}
+
+func (c *Client) ListHeartRate(ctx context.Context, start, end time.Time) ([]HeartRate, error) {
+	params := map[string]string{}
+	if !start.IsZero() {
+		params["start_datetime"] = start.Format(time.RFC3339)
+	}
+	if !end.IsZero() {
+		params["end_datetime"] = end.Format(time.RFC3339)
+	}
+	return ListAll[HeartRate](ctx, c, BuildPath(c.sandbox, "heartrate"), BuildQuery(params))
+}
+
+func cloneValues(query url.Values) url.Values {
+	out := url.Values{}
+	for k, v := range query {
+		out[k] = append([]string(nil), v...)
+	}
+	return out
+}